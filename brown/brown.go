@@ -0,0 +1,10 @@
+// Package brown embeds the files of the Brown Corpus kept in this directory,
+// so the tagger can be trained without the corpus being on disk.
+package brown
+
+import "embed"
+
+// Files holds every corpus file, named as they are in this directory
+//
+//go:embed c* mik01
+var Files embed.FS
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/modquiz/go-nltb/lib/corpus/brown"
+	"github.com/modquiz/go-nltb/lib/ner"
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// Entity types of the English model, the names MITIE's English model uses
+const (
+	person       = "PERSON"
+	location     = "LOCATION"
+	organization = "ORGANIZATION"
+	misc         = "MISC"
+)
+
+// Proper nouns of the Brown Corpus that are not entities
+var notEntities = map[string]bool{
+	"january": true, "february": true, "march": true, "april": true, "may": true, "june": true,
+	"july": true, "august": true, "september": true, "october": true, "november": true, "december": true,
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true, "saturday": true, "sunday": true,
+}
+
+// Words that start the names of places, as in Lake Michigan
+var placePrefixes = map[string]bool{"lake": true, "mount": true, "mt.": true, "fort": true, "ft.": true, "cape": true, "port": true, "camp": true}
+
+// Words that join the words of a title, like of in University of Chicago
+var connectors = map[string]bool{"of": true, "and": true, "&": true, "the": true, "for": true, "de": true, "on": true}
+
+// Labels the Brown Corpus with entities. The Brown tags mark proper nouns
+// (np) and the words of titles (-tl), the runs of them are typed by the
+// gazetteer, by their last word like County or Company and by the titles in
+// front of people. A name that was a person earlier in the same file is a
+// person again, a single name after in, near, from or at is a place.
+// Sentences with a run that can not be typed are left out rather than
+// labelled wrong.
+func labelBrown(gazetteer *ner.Gazetteer) ([][]tagger.TaggedWord, error) {
+	var labelled [][]tagger.TaggedWord
+	reader := brown.New()
+	for _, fileid := range reader.FileIDs() {
+		sentences, err := readBrownFile(reader, fileid)
+		if err != nil {
+			return nil, err
+		}
+		l := labeller{gazetteer: gazetteer, people: make(map[string]bool)}
+		// the first pass learns the people of the file
+		for _, sentence := range sentences {
+			l.label(sentence)
+		}
+		for _, sentence := range sentences {
+			if labels, ok := l.label(sentence); ok {
+				for i := range sentence {
+					sentence[i].Tag = labels[i]
+				}
+				labelled = append(labelled, sentence)
+			}
+		}
+	}
+	return labelled, nil
+}
+
+// Reads the sentences of a file with possessives split off like the
+// Treebank tokenizer does, Atlanta's is Atlanta 's
+func readBrownFile(reader *brown.Reader, fileid string) ([][]tagger.TaggedWord, error) {
+	var sentences [][]tagger.TaggedWord
+	sents := reader.TaggedSents(brown.Filter{FileIDs: []string{fileid}})
+	defer sents.Close()
+	for {
+		sentence, err := sents.Next()
+		if err == io.EOF {
+			return sentences, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var split []tagger.TaggedWord
+		for _, word := range sentence {
+			tag := word.Tag
+			if strings.Contains(tag, "$") && len(word.Word) > 2 {
+				for _, possessive := range []string{"'s", "'"} {
+					if strings.HasSuffix(word.Word, possessive) {
+						split = append(split, tagger.TaggedWord{Word: strings.TrimSuffix(word.Word, possessive), Tag: strings.Replace(tag, "$", "", 1)})
+						word = tagger.TaggedWord{Word: possessive, Tag: "$"}
+						break
+					}
+				}
+			}
+			switch word.Word {
+			case "``", "''":
+				word.Word = "\""
+			}
+			split = append(split, word)
+		}
+		sentences = append(sentences, split)
+	}
+}
+
+type labeller struct {
+	gazetteer *ner.Gazetteer
+	// the words of the people found so far in the file
+	people map[string]bool
+}
+
+// Returns the BIO labels of the sentence, false when a run of proper nouns
+// could not be typed
+func (l *labeller) label(sentence []tagger.TaggedWord) ([]string, bool) {
+	labels := make([]string, len(sentence))
+	for i := range labels {
+		labels[i] = "O"
+	}
+	ok := true
+	for _, run := range properRuns(sentence) {
+		for _, segment := range l.segments(sentence, run) {
+			entityType, typed := l.entityType(sentence[segment[0]:segment[1]])
+			if !typed && isPlaceContext(sentence, segment) {
+				entityType, typed = location, true
+			}
+			if !typed {
+				ok = false
+				continue
+			}
+			if entityType == "" {
+				continue
+			}
+			labels[segment[0]] = "B-" + entityType
+			for i := segment[0] + 1; i < segment[1]; i++ {
+				labels[i] = "I-" + entityType
+			}
+			if entityType == person {
+				l.markPeople(sentence[segment[0]:segment[1]])
+			}
+		}
+	}
+	return labels, ok
+}
+
+// Words in front of a place, as in "lives in Dallas"
+var placePrepositions = map[string]bool{"in": true, "near": true, "from": true, "at": true}
+
+// Whether the segment is a single proper noun after one of the
+// placePrepositions, like Dallas in "born in Dallas, Texas" but not Smith in
+// "at Smith's store" or "in Smith Hall"
+func isPlaceContext(sentence []tagger.TaggedWord, segment [2]int) bool {
+	if segment[1]-segment[0] != 1 || segment[0] == 0 || segment[1] == len(sentence) {
+		return false
+	}
+	if !placePrepositions[strings.ToLower(sentence[segment[0]-1].Word)] || strings.Contains(sentence[segment[0]].Tag, "-tl") {
+		return false
+	}
+	next := sentence[segment[1]]
+	return next.Word != "'s" && next.Word != "'" && !isProper(next.Tag)
+}
+
+// Whether the tag is a proper noun, np with any of its suffixes
+func isProper(tag string) bool {
+	return strings.HasPrefix(tag, "np")
+}
+
+// Whether the word is part of a title, capitalised and tagged -tl
+func isTitle(word tagger.TaggedWord) bool {
+	first := []rune(word.Word)[0]
+	return strings.Contains(word.Tag, "-tl") && unicode.IsUpper(first)
+}
+
+// The runs of proper nouns and title words, as start and end indexes.
+// Connectors like of are only part of a run between two title words.
+func properRuns(sentence []tagger.TaggedWord) [][2]int {
+	var runs [][2]int
+	for i := 0; i < len(sentence); {
+		if !isProper(sentence[i].Tag) && !isTitle(sentence[i]) {
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(sentence) {
+			if isProper(sentence[end].Tag) || isTitle(sentence[end]) {
+				end++
+				continue
+			}
+			// of in University of Chicago is tagged in-tl
+			next := end
+			for next < len(sentence) && connectors[strings.ToLower(sentence[next].Word)] && strings.Contains(sentence[next].Tag, "-tl") {
+				next++
+			}
+			if next > end && next < len(sentence) && (isProper(sentence[next].Tag) || isTitle(sentence[next])) {
+				end = next
+				continue
+			}
+			break
+		}
+		runs = append(runs, [2]int{i, end})
+		i = end
+	}
+	return runs
+}
+
+// Splits a run where it goes from title words to plain proper nouns, as in
+// Superior Court Judge Durwood Pye, and drops the titles of people: a title
+// word like Judge or Mr. in front of a name, or a whole title like Secretary
+// of State, is outside of every entity and makes the name a person.
+func (l *labeller) segments(sentence []tagger.TaggedWord, run [2]int) [][2]int {
+	var parts [][2]int
+	start := run[0]
+	for i := run[0] + 1; i < run[1]; i++ {
+		if strings.Contains(sentence[i-1].Tag, "-tl") != strings.Contains(sentence[i].Tag, "-tl") {
+			parts = append(parts, [2]int{start, i})
+			start = i
+		}
+	}
+	parts = append(parts, [2]int{start, run[1]})
+
+	var segments [][2]int
+	isTitleWord := func(i int) bool { return l.gazetteer.Contains(ner.TitleList, sentence[i].Word) }
+	for p, part := range parts {
+		beforeName := p+1 < len(parts)
+		if isTitleWord(part[0]) && strings.Contains(sentence[part[0]].Tag, "-tl") && beforeName {
+			// Secretary of State Rusk
+			part[0] = part[1]
+		}
+		for part[0] < part[1] && isTitleWord(part[0]) {
+			part[0]++
+			beforeName = true
+		}
+		for part[1] > part[0] && isTitleWord(part[1]-1) && p+1 < len(parts) {
+			// Superior Court Judge Durwood Pye
+			part[1]--
+		}
+		for part[0] < part[1] && connectors[strings.ToLower(sentence[part[0]].Word)] {
+			part[0]++
+		}
+		for part[1] > part[0] && connectors[strings.ToLower(sentence[part[1]-1].Word)] {
+			part[1]--
+		}
+		if part[0] == part[1] {
+			if beforeName && p+1 < len(parts) {
+				l.markPeople(sentence[parts[p+1][0]:parts[p+1][1]])
+			}
+			continue
+		}
+		if part[0] > parts[p][0] && isProper(sentence[part[0]].Tag) {
+			l.markPeople(sentence[part[0]:part[1]])
+		}
+		segments = append(segments, part)
+	}
+	return segments
+}
+
+// Remembers the words of a name as the words of people
+func (l *labeller) markPeople(words []tagger.TaggedWord) {
+	for _, word := range words {
+		l.people[word.Word] = true
+	}
+}
+
+// The type of the words of a segment, "" when it is not an entity and false
+// when it can not be told
+func (l *labeller) entityType(words []tagger.TaggedWord) (string, bool) {
+	tokens := make([]string, len(words))
+	proper := false
+	for i, word := range words {
+		tokens[i] = word.Word
+		proper = proper || isProper(word.Tag)
+	}
+	g := l.gazetteer
+	last := tokens[len(tokens)-1]
+	switch {
+	case len(tokens) == 1 && notEntities[strings.ToLower(last)]:
+		return "", true
+	case g.Contains(ner.OrganizationList, tokens...):
+		return organization, true
+	case g.Contains(ner.LocationList, tokens...):
+		return location, true
+	case g.Contains(ner.MiscList, tokens...):
+		return misc, true
+	case len(tokens) > 1 && containsAny(g, ner.OrgWordList, tokens):
+		return organization, true
+	case len(tokens) > 1 && (g.Contains(ner.LocWordList, last) || placePrefixes[strings.ToLower(tokens[0])] && proper):
+		return location, true
+	case !proper:
+		// a lone title word like President or Federal is not a name
+		if len(tokens) == 1 {
+			return "", true
+		}
+		return "", false
+	case g.Contains(ner.PersonList, tokens[0]) && (len(tokens) > 1 || isProper(words[0].Tag)):
+		return person, true
+	case allPeople(l.people, tokens):
+		return person, true
+	case len(tokens) > 1 && allProper(words) && !containsAny(g, ner.LocationList, tokens):
+		return person, true
+	}
+	return "", false
+}
+
+func containsAny(g *ner.Gazetteer, list string, tokens []string) bool {
+	for _, token := range tokens {
+		if g.Contains(list, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func allPeople(people map[string]bool, tokens []string) bool {
+	for _, token := range tokens {
+		if !people[token] {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether every word is a plain proper noun, capitalised and not a title
+func allProper(words []tagger.TaggedWord) bool {
+	for _, word := range words {
+		if !isProper(word.Tag) || strings.Contains(word.Tag, "-tl") || !unicode.IsUpper([]rune(word.Word)[0]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+// Command ner-train trains the pure Go named entity recognizer of lib/ner
+// and saves it for ner.LoadRecognizer.
+//
+// The files are read as CoNLL data. Without files it trains on the embedded
+// Brown Corpus, labelled from its proper noun tags and the English
+// gazetteer, which is how lib/ner/english.model is made:
+//
+//	ner-train -out english.model
+//	ner-train -out conll.model -test eng.testb eng.train
+//
+// -test reports the precision, recall and F1 of the entities of a CoNLL
+// file, and -dump writes the training sentences as CoNLL to look at.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/modquiz/go-nltb/lib/ner"
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+func main() {
+	out := flag.String("out", "", "file the model is written to")
+	test := flag.String("test", "", "CoNLL file to evaluate the model on")
+	dump := flag.String("dump", "", "file the training sentences are written to as CoNLL")
+	iterations := flag.Int("iterations", 5, "training iterations")
+	seed := flag.Int64("seed", 1, "seed of the shuffle between iterations")
+	flag.Parse()
+
+	if err := run(*out, *test, *dump, *iterations, *seed, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "ner-train:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, test string, dump string, iterations int, seed int64, files []string) error {
+	var sentences [][]tagger.TaggedWord
+	var err error
+	if len(files) == 0 {
+		sentences, err = labelBrown(ner.EnglishGazetteer())
+	} else {
+		sentences, err = readCoNLLFiles(files...)
+	}
+	if err != nil {
+		return err
+	}
+	if dump != "" {
+		if err := writeCoNLLFile(dump, sentences); err != nil {
+			return err
+		}
+	}
+
+	recognizer, err := ner.Train(tagger.NewSliceSentenceReader(sentences), ner.WithIterations(iterations), ner.WithSeed(seed))
+	if err != nil {
+		return err
+	}
+	if out != "" {
+		if err := recognizer.SaveFile(out); err != nil {
+			return err
+		}
+	}
+	if test != "" {
+		gold, err := readCoNLLFiles(test)
+		if err != nil {
+			return err
+		}
+		evaluate(recognizer, gold).write(os.Stdout)
+	}
+	return nil
+}
+
+func readCoNLLFiles(files ...string) ([][]tagger.TaggedWord, error) {
+	var sentences [][]tagger.TaggedWord
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		reader := ner.NewCoNLLReader(f)
+		for {
+			sentence, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			sentences = append(sentences, sentence)
+		}
+		f.Close()
+	}
+	return sentences, nil
+}
+
+func writeCoNLLFile(path string, sentences [][]tagger.TaggedWord) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, sentence := range sentences {
+		for _, word := range sentence {
+			fmt.Fprintf(w, "%s %s\n", word.Word, word.Tag)
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// The entity counts of an evaluation, by type
+type scores struct {
+	gold, predicted, correct map[string]int
+}
+
+// Compares the entities the recognizer finds to the gold ones, an entity is
+// only correct with the same span and type
+func evaluate(recognizer *ner.Recognizer, gold [][]tagger.TaggedWord) scores {
+	s := scores{gold: make(map[string]int), predicted: make(map[string]int), correct: make(map[string]int)}
+	for _, sentence := range gold {
+		tokens := make([]string, len(sentence))
+		labels := make([]string, len(sentence))
+		for i := range sentence {
+			tokens[i], labels[i] = sentence[i].Word, sentence[i].Tag
+		}
+		want := spans(labels)
+		for span := range want {
+			s.gold[span.entityType]++
+		}
+		for span := range spans(recognizer.Label(tokens)) {
+			s.predicted[span.entityType]++
+			if want[span] {
+				s.correct[span.entityType]++
+			}
+		}
+	}
+	return s
+}
+
+type span struct {
+	start, end int
+	entityType string
+}
+
+// The entities of a BIO labelled sentence
+func spans(labels []string) map[span]bool {
+	found := make(map[span]bool)
+	for start := 0; start < len(labels); {
+		if !strings.HasPrefix(labels[start], "B-") {
+			start++
+			continue
+		}
+		entityType := labels[start][2:]
+		end := start + 1
+		for end < len(labels) && labels[end] == "I-"+entityType {
+			end++
+		}
+		found[span{start, end, entityType}] = true
+		start = end
+	}
+	return found
+}
+
+func (s scores) write(w io.Writer) {
+	var types []string
+	for entityType := range s.gold {
+		types = append(types, entityType)
+	}
+	for entityType := range s.predicted {
+		if _, ok := s.gold[entityType]; !ok {
+			types = append(types, entityType)
+		}
+	}
+	sort.Strings(types)
+	fmt.Fprintf(w, "%-14s %9s %9s %9s %7s\n", "type", "precision", "recall", "f1", "support")
+	correct, predicted, gold := 0, 0, 0
+	for _, entityType := range types {
+		writeScore(w, entityType, s.correct[entityType], s.predicted[entityType], s.gold[entityType])
+		correct += s.correct[entityType]
+		predicted += s.predicted[entityType]
+		gold += s.gold[entityType]
+	}
+	writeScore(w, "all", correct, predicted, gold)
+}
+
+func writeScore(w io.Writer, name string, correct int, predicted int, gold int) {
+	precision, recall, f1 := ratio(correct, predicted), ratio(correct, gold), 0.0
+	if precision+recall > 0 {
+		f1 = 2 * precision * recall / (precision + recall)
+	}
+	fmt.Fprintf(w, "%-14s %9.4f %9.4f %9.4f %7d\n", name, precision, recall, f1, gold)
+}
+
+func ratio(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
@@ -0,0 +1,116 @@
+// Command punkt-train learns the parameters of a Punkt sentence segmenter
+// from raw text and writes them as JSON for punkt.ReadJSON.
+//
+// Without arguments it trains on the embedded Brown Corpus, put back
+// together into running text, which is how lib/punkt/english.json is made:
+//
+//	punkt-train -out english.json
+//	punkt-train -out legal.json contracts/*.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/modquiz/go-nltb/lib/corpus/brown"
+	"github.com/modquiz/go-nltb/lib/punkt"
+)
+
+func main() {
+	out := flag.String("out", "", "file the parameters are written to, standard output when empty")
+	flag.Parse()
+
+	if err := run(*out, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "punkt-train:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, files []string) error {
+	trainer := punkt.NewTrainer()
+	if len(files) == 0 {
+		if err := trainBrown(trainer); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		text, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		trainer.Train(string(text))
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return trainer.Parameters().WriteJSON(w)
+}
+
+// Trains on the Brown Corpus one file at a time, with the words of each
+// sentence joined back into text and paragraphs separated by blank lines
+func trainBrown(trainer *punkt.Trainer) error {
+	reader := brown.New()
+	for _, fileid := range reader.FileIDs() {
+		paras := reader.Paras(brown.Filter{FileIDs: []string{fileid}})
+		var text strings.Builder
+		for {
+			para, err := paras.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				paras.Close()
+				return err
+			}
+			for _, sent := range para {
+				text.WriteString(detokenize(sent))
+				text.WriteString(" ")
+			}
+			text.WriteString("\n\n")
+		}
+		paras.Close()
+		trainer.Train(text.String())
+	}
+	return nil
+}
+
+// Joins words into text, without the spaces the corpus puts around
+// punctuation
+func detokenize(words []string) string {
+	var text strings.Builder
+	space := false
+	for i, word := range words {
+		// the corpus ends a sentence after Jr. with a period of its own
+		if word == "." && i > 0 && strings.HasSuffix(words[i-1], ".") {
+			continue
+		}
+		switch word {
+		case "``", "''":
+			word = "\""
+		}
+		attach := false
+		switch word {
+		case ".", ",", ";", ":", "?", "!", ")", "]":
+			attach = true
+		case "\"":
+			// closing quotes follow the word they close
+			attach = strings.Count(text.String(), "\"")%2 == 1
+		}
+		if space && !attach {
+			text.WriteString(" ")
+		}
+		text.WriteString(word)
+		space = word != "(" && word != "[" && !(word == "\"" && !attach)
+	}
+	return text.String()
+}
@@ -0,0 +1,173 @@
+// Command tagger-eval trains a part of speech tagger, or loads a saved one,
+// and reports how well it tags a held-out part of a corpus.
+//
+// By default the embedded Brown Corpus is split by category, the files
+// matching -test are held out and the tagger is trained on the rest:
+//
+//	tagger-eval -test 'cr*' -order 2
+//	tagger-eval -corpus ./mycorpus -test 'dev*' -json
+//	tagger-eval -model brown.model -corpus ./heldout -test '*'
+//
+// With -folds the whole corpus is cross-validated instead, by file:
+//
+//	tagger-eval -folds 10 -seed 7
+//
+// -tagger perceptron evaluates the averaged perceptron instead of the HMM:
+//
+//	tagger-eval -tagger perceptron -iterations 5
+//
+// -tagger brill learns Brill rules on top of the HMM and prints them:
+//
+//	tagger-eval -tagger brill -rules 100
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/modquiz/go-nltb/brown"
+	"github.com/modquiz/go-nltb/lib/brill"
+	"github.com/modquiz/go-nltb/lib/corpus"
+	"github.com/modquiz/go-nltb/lib/perceptron"
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+func main() {
+	corpusDir := flag.String("corpus", "", "directory of word/tag files, the embedded Brown Corpus when empty")
+	test := flag.String("test", "cr*", "pattern of the corpus files held out for testing")
+	model := flag.String("model", "", "evaluate a model saved with Tagger.SaveFile instead of training one")
+	kind := flag.String("tagger", "hmm", "the tagger to train, hmm, perceptron or brill")
+	order := flag.Int("order", 1, "1 for a bigram model, 2 for a trigram model")
+	iterations := flag.Int("iterations", 5, "training iterations of the perceptron")
+	rules := flag.Int("rules", 100, "maximum number of Brill rules to learn")
+	asJSON := flag.Bool("json", false, "write the report as JSON")
+	confusions := flag.Int("confusions", 20, "number of most frequent mistakes in the text report")
+	folds := flag.Int("folds", 0, "cross-validate over this many folds of the corpus files instead")
+	seed := flag.Int64("seed", 1, "seed of the cross-validation folds")
+	flag.Parse()
+
+	var trainFunc corpus.TrainFunc
+	var loadFunc func(string) (tagger.SequenceTagger, error)
+	switch *kind {
+	case "hmm":
+		trainFunc = corpus.HMMTrainer(tagger.WithOrder(tagger.Order(*order)))
+		loadFunc = func(path string) (tagger.SequenceTagger, error) { return tagger.LoadFile(path) }
+	case "perceptron":
+		trainFunc = func(sentences tagger.SentenceReader) (tagger.SequenceTagger, error) {
+			return perceptron.Train(sentences, perceptron.WithIterations(*iterations), perceptron.WithSeed(*seed))
+		}
+		loadFunc = func(path string) (tagger.SequenceTagger, error) { return perceptron.LoadFile(path) }
+	case "brill":
+		trainFunc = func(sentences tagger.SentenceReader) (tagger.SequenceTagger, error) {
+			// the sentences are read twice, once for the HMM and once for the rules
+			all, err := corpus.ReadSentences(sentences)
+			if err != nil {
+				return nil, err
+			}
+			hmm, err := tagger.Train(tagger.NewSliceSentenceReader(all), tagger.WithOrder(tagger.Order(*order)))
+			if err != nil {
+				return nil, err
+			}
+			brillTagger, err := brill.Train(hmm, tagger.NewSliceSentenceReader(all), brill.WithMaxRules(*rules))
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range brillTagger.Rules() {
+				fmt.Fprintf(os.Stderr, "%4d  %s\n", rule.Score, rule)
+			}
+			return brillTagger, nil
+		}
+		loadFunc = func(path string) (tagger.SequenceTagger, error) {
+			return nil, errors.New("brill can not be loaded from a model, train it instead")
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "tagger-eval: unknown tagger %q\n", *kind)
+		os.Exit(2)
+	}
+
+	var err error
+	if *folds > 0 {
+		err = crossValidate(*corpusDir, *folds, *seed, trainFunc, *asJSON, *confusions)
+	} else {
+		err = run(*corpusDir, *test, *model, trainFunc, loadFunc, *asJSON, *confusions)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tagger-eval:", err)
+		os.Exit(1)
+	}
+}
+
+// Lists the corpus files, the embedded Brown Corpus when dir is empty
+func corpusFiles(dir string) (fs.FS, []string, error) {
+	var fsys fs.FS = brown.Files
+	if dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	files, err := tagger.CorpusFiles(fsys, ".")
+	return fsys, files, err
+}
+
+func run(corpusDir string, test string, model string, train corpus.TrainFunc, load func(string) (tagger.SequenceTagger, error), asJSON bool, confusions int) error {
+	fsys, files, err := corpusFiles(corpusDir)
+	if err != nil {
+		return err
+	}
+
+	var trainFiles, testFiles []string
+	for _, name := range files {
+		held, err := path.Match(test, path.Base(name))
+		if err != nil {
+			return err
+		}
+		if held {
+			testFiles = append(testFiles, name)
+		} else {
+			trainFiles = append(trainFiles, name)
+		}
+	}
+	if len(testFiles) == 0 {
+		return fmt.Errorf("no corpus files match %q", test)
+	}
+
+	var posTagger tagger.SequenceTagger
+	if model != "" {
+		posTagger, err = load(model)
+	} else {
+		posTagger, err = train(tagger.NewFSSentenceReader(fsys, trainFiles...))
+	}
+	if err != nil {
+		return err
+	}
+
+	eval, err := tagger.Evaluate(posTagger, tagger.NewFSSentenceReader(fsys, testFiles...))
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return eval.WriteJSON(os.Stdout)
+	}
+	return eval.WriteText(os.Stdout, confusions)
+}
+
+func crossValidate(corpusDir string, k int, seed int64, train corpus.TrainFunc, asJSON bool, confusions int) error {
+	fsys, files, err := corpusFiles(corpusDir)
+	if err != nil {
+		return err
+	}
+	folds, err := corpus.FileFolds(fsys, files, k, seed)
+	if err != nil {
+		return err
+	}
+	cv, err := corpus.CrossValidate(folds, train)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return cv.WriteJSON(os.Stdout)
+	}
+	return cv.WriteText(os.Stdout, confusions)
+}
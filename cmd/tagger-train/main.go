@@ -0,0 +1,41 @@
+// Command tagger-train trains the HMM part of speech tagger of lib/tagger and
+// saves it for tagger.LoadFile.
+//
+// Without -corpus it trains on the embedded Brown Corpus, which is how
+// lib/tagger/brown.model is made:
+//
+//	tagger-train -out brown.model
+//	tagger-train -out trigram.model -order 2 -corpus my-corpus
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+func main() {
+	out := flag.String("out", "", "file the model is written to")
+	corpusDir := flag.String("corpus", "", "directory of word/tag files, the embedded Brown Corpus when empty")
+	order := flag.Int("order", 1, "1 for a bigram model, 2 for a trigram model")
+	flag.Parse()
+
+	if err := run(*out, *corpusDir, tagger.Order(*order)); err != nil {
+		fmt.Fprintln(os.Stderr, "tagger-train:", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, corpusDir string, order tagger.Order) error {
+	if out == "" {
+		return errors.New("no -out file given")
+	}
+	posTagger, err := tagger.New(corpusDir, tagger.WithOrder(order))
+	if err != nil {
+		return err
+	}
+	return posTagger.SaveFile(out)
+}
@@ -0,0 +1,93 @@
+package nltb
+
+import (
+	"context"
+	"sync"
+)
+
+// The tagged document at position Index of the documents given to
+// DoConcurrent
+type DocumentResult struct {
+	Index    int
+	Document *Document
+}
+
+/* Tags the documents received from the channel with at most workers goroutines sharing the model. Results are sent as they are done, so not in order, and the channel is closed once documents is closed or ctx is cancelled */
+func (p *POSTag) DoConcurrent(ctx context.Context, documents <-chan []byte, workers int) <-chan DocumentResult {
+	if workers < 1 {
+		workers = 1
+	}
+	type job struct {
+		index    int
+		document []byte
+	}
+	jobs := make(chan job)
+	results := make(chan DocumentResult)
+
+	// number the documents in the order they arrive
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case <-ctx.Done():
+				return
+			case document, ok := <-documents:
+				if !ok {
+					return
+				}
+				select {
+				case jobs <- job{index: index, document: document}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case results <- DocumentResult{Index: j.index, Document: p.DoDocument(j.document)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+/* Tags many documents with at most workers goroutines, the result for documents[i] is at index i. When ctx is cancelled the documents not tagged yet are left nil and the error of ctx is returned */
+func (p *POSTag) DoBatchContext(ctx context.Context, documents [][]byte, workers int) ([]*Document, error) {
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	input := make(chan []byte)
+	go func() {
+		defer close(input)
+		for _, document := range documents {
+			select {
+			case input <- document:
+			case <-batchCtx.Done():
+				return
+			}
+		}
+	}()
+
+	docs := make([]*Document, len(documents))
+	for result := range p.DoConcurrent(batchCtx, input, workers) {
+		docs[result.Index] = result.Document
+	}
+	return docs, ctx.Err()
+}
@@ -0,0 +1,33 @@
+package nltb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPOSTag_DoBatchContext(t *testing.T) {
+	posTagger := POSTag{}
+	posTagger.Init()
+
+	documents := make([][]byte, 20)
+	for i := range documents {
+		documents[i] = []byte(fmt.Sprintf("The jury met %d times. It said nothing.\n\nNot one word.", i))
+	}
+	docs, err := posTagger.DoBatchContext(context.Background(), documents, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, document := range documents {
+		if want := posTagger.DoDocument(document); !reflect.DeepEqual(docs[i], want) {
+			t.Errorf("DoBatchContext() document %d = %+v, want %+v", i, docs[i], want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := posTagger.DoBatchContext(ctx, documents, 4); err != context.Canceled {
+		t.Errorf("DoBatchContext() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+}
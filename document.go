@@ -0,0 +1,174 @@
+package nltb
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/modquiz/go-nltb/lib/punkt"
+)
+
+// A tagged document, paragraphs are separated by blank lines
+type Document struct {
+	Paragraphs []Paragraph
+}
+
+// A paragraph of a Document, its offsets are into the whole document
+type Paragraph struct {
+	Sentences []Sentence
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+}
+
+// A tagged sentence of a Paragraph, its offsets and those of its words are
+// into the whole document
+type Sentence struct {
+	Text      string
+	Words     []TaggedWord
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+}
+
+/* Returns the sentences of every paragraph in order */
+func (d *Document) Sentences() []Sentence {
+	sentences := make([]Sentence, 0)
+	for _, paragraph := range d.Paragraphs {
+		sentences = append(sentences, paragraph.Sentences...)
+	}
+	return sentences
+}
+
+/* Returns the tagged words of the whole document in order */
+func (d *Document) Words() []TaggedWord {
+	words := make([]TaggedWord, 0)
+	for _, paragraph := range d.Paragraphs {
+		for _, sentence := range paragraph.Sentences {
+			words = append(words, sentence.Words...)
+		}
+	}
+	return words
+}
+
+/* Splits a document into paragraphs and sentences and tags every sentence */
+func (p *POSTag) DoDocument(document []byte) *Document {
+	text := string(document)
+	doc := &Document{Paragraphs: make([]Paragraph, 0)}
+	for _, paragraph := range splitParagraphs(text) {
+		doc.Paragraphs = append(doc.Paragraphs, p.tagParagraph(text, paragraph))
+	}
+	return doc
+}
+
+/* Reads the whole of r and tags it like DoDocument */
+func (p *POSTag) DoReader(r io.Reader) (*Document, error) {
+	document, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return p.DoDocument(document), nil
+}
+
+/* Tags many documents in parallel, the result for documents[i] is at index i */
+func (p *POSTag) DoBatch(documents [][]byte) []*Document {
+	docs, _ := p.DoBatchContext(context.Background(), documents, runtime.GOMAXPROCS(0))
+	return docs
+}
+
+// Sentences longer than this are split at a space before they are tagged,
+// the Viterbi lattice grows with the length of a sentence
+const maxSentenceBytes = 8 << 10
+
+// Splits the paragraph of text into sentences and tags them
+func (p *POSTag) tagParagraph(text string, paragraph Paragraph) Paragraph {
+	for _, sentence := range punkt.English().Sentences(text[paragraph.ByteStart:paragraph.ByteEnd]) {
+		byteStart := paragraph.ByteStart + sentence.ByteStart
+		runeStart := paragraph.RuneStart + sentence.RuneStart
+		for _, piece := range splitLong(sentence.Text) {
+			paragraph.Sentences = append(paragraph.Sentences, Sentence{
+				Text:      piece,
+				Words:     p.tagSentence(piece, byteStart, runeStart),
+				ByteStart: byteStart,
+				ByteEnd:   byteStart + len(piece),
+				RuneStart: runeStart,
+				RuneEnd:   runeStart + utf8.RuneCountInString(piece),
+			})
+			byteStart += len(piece)
+			runeStart += utf8.RuneCountInString(piece)
+		}
+	}
+	return paragraph
+}
+
+// Cuts a sentence longer than maxSentenceBytes into pieces, each ends after
+// a space when there is one
+func splitLong(sentence string) []string {
+	pieces := make([]string, 0, 1)
+	for len(sentence) > maxSentenceBytes {
+		cut := maxSentenceBytes
+		if space := strings.LastIndexFunc(sentence[:cut], unicode.IsSpace); space >= 0 {
+			_, size := utf8.DecodeRuneInString(sentence[space:])
+			cut = space + size
+		} else {
+			// no space, cut at the start of a rune
+			for !utf8.RuneStart(sentence[cut]) {
+				cut--
+			}
+		}
+		pieces = append(pieces, sentence[:cut])
+		sentence = sentence[cut:]
+	}
+	return append(pieces, sentence)
+}
+
+// Tags one sentence found at the given offsets of a larger text and moves the
+// offsets of its words there
+func (p *POSTag) tagSentence(sentence string, byteStart int, runeStart int) []TaggedWord {
+	words := p.DoTagset([]byte(sentence), p.Tagset)
+	for i := range words {
+		words[i].ByteStart += byteStart
+		words[i].ByteEnd += byteStart
+		words[i].RuneStart += runeStart
+		words[i].RuneEnd += runeStart
+	}
+	return words
+}
+
+// Splits text at blank lines into paragraphs without their surrounding
+// space, the sentences are left empty
+func splitParagraphs(text string) []Paragraph {
+	paragraphs := make([]Paragraph, 0)
+	start, end := -1, -1
+	blankLine := false
+	currRune, startRune, endRune := 0, 0, 0
+	for i := 0; i < len(text); currRune++ {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\n':
+			if blankLine && start >= 0 {
+				paragraphs = append(paragraphs, Paragraph{ByteStart: start, ByteEnd: end, RuneStart: startRune, RuneEnd: endRune})
+				start = -1
+			}
+			blankLine = true
+		case unicode.IsSpace(r):
+		default:
+			if start < 0 {
+				start, startRune = i, currRune
+			}
+			end, endRune = i+size, currRune+1
+			blankLine = false
+		}
+		i += size
+	}
+	if start >= 0 {
+		paragraphs = append(paragraphs, Paragraph{ByteStart: start, ByteEnd: end, RuneStart: startRune, RuneEnd: endRune})
+	}
+	return paragraphs
+}
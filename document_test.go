@@ -0,0 +1,56 @@
+package nltb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_splitParagraphs(t *testing.T) {
+	text := "\n  First line.\nSecond line.\n \t\nNaïve café.\n\n\n"
+	var got []string
+	for _, paragraph := range splitParagraphs(text) {
+		got = append(got, text[paragraph.ByteStart:paragraph.ByteEnd])
+		if []rune(text)[paragraph.RuneStart] != []rune(got[len(got)-1])[0] {
+			t.Errorf("paragraph %q starts at rune %d", got[len(got)-1], paragraph.RuneStart)
+		}
+	}
+	want := []string{"First line.\nSecond line.", "Naïve café."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitParagraphs() = %q, want %q", got, want)
+	}
+}
+
+func TestPOSTag_DoDocument(t *testing.T) {
+	posTagger := POSTag{}
+	posTagger.Init()
+
+	document := "The jury met Mr. Smith. He said nothing.\n\nThe café was closed."
+	doc := posTagger.DoDocument([]byte(document))
+	if len(doc.Paragraphs) != 2 || len(doc.Paragraphs[0].Sentences) != 2 || len(doc.Paragraphs[1].Sentences) != 1 {
+		t.Fatalf("DoDocument() = %+v", doc)
+	}
+	for _, sentence := range doc.Sentences() {
+		if document[sentence.ByteStart:sentence.ByteEnd] != sentence.Text {
+			t.Errorf("sentence %q has offsets %d:%d", sentence.Text, sentence.ByteStart, sentence.ByteEnd)
+		}
+	}
+	runes := []rune(document)
+	for _, word := range doc.Words() {
+		if document[word.ByteStart:word.ByteEnd] != word.Word || string(runes[word.RuneStart:word.RuneEnd]) != word.Word {
+			t.Errorf("word %q has offsets %d:%d", word.Word, word.ByteStart, word.ByteEnd)
+		}
+	}
+	if words := doc.Words(); words[0].Tag != "at" || words[len(words)-1].Tag != "." {
+		t.Errorf("DoDocument() tagged %+v", words)
+	}
+
+	docs := posTagger.DoBatch([][]byte{[]byte(document), []byte("")})
+	if len(docs) != 2 || !reflect.DeepEqual(docs[0], doc) || len(docs[1].Paragraphs) != 0 {
+		t.Errorf("DoBatch() = %+v", docs)
+	}
+	fromReader, err := posTagger.DoReader(strings.NewReader(document))
+	if err != nil || !reflect.DeepEqual(fromReader, doc) {
+		t.Errorf("DoReader() = %+v, %v", fromReader, err)
+	}
+}
@@ -0,0 +1,191 @@
+// Package brill is a transformation-based tagger (Brill 1995). It starts
+// from the tags of another tagger, usually the HMM of lib/tagger, and fixes
+// them with rules like "change nn to vb when the tag before is to" learned
+// from the mistakes that tagger makes on a training corpus.
+package brill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// The templates rules are made from, each looks at one feature around the
+// word being retagged
+const (
+	PrevTag     = "prev-tag"
+	NextTag     = "next-tag"
+	Prev2Tag    = "prev2-tag"
+	Next2Tag    = "next2-tag"
+	SurroundTag = "surround-tag"
+	Word        = "word"
+	PrevWord    = "prev-word"
+	NextWord    = "next-word"
+	Suffix1     = "suffix1"
+	Suffix2     = "suffix2"
+	Suffix3     = "suffix3"
+)
+
+// DefaultTemplates are the templates Train uses unless told otherwise
+var DefaultTemplates = []string{PrevTag, NextTag, Prev2Tag, Next2Tag, SurroundTag, Word, PrevWord, NextWord, Suffix1, Suffix2, Suffix3}
+
+// ErrUnknownTemplate is returned for rules and options naming a template
+// that does not exist
+var ErrUnknownTemplate = errors.New("Unknown rule template")
+
+// Returns the feature of a template at index, false when it is off the
+// edge of the sentence
+type feature func(words []string, tags []string, index int) (string, bool)
+
+var templates = map[string]feature{
+	PrevTag:  tagAt(-1),
+	NextTag:  tagAt(1),
+	Prev2Tag: tagAt(-2),
+	Next2Tag: tagAt(2),
+	SurroundTag: func(words []string, tags []string, index int) (string, bool) {
+		if index == 0 || index+1 >= len(tags) {
+			return "", false
+		}
+		return tags[index-1] + " " + tags[index+1], true
+	},
+	Word:     wordAt(0),
+	PrevWord: wordAt(-1),
+	NextWord: wordAt(1),
+	Suffix1:  suffix(1),
+	Suffix2:  suffix(2),
+	Suffix3:  suffix(3),
+}
+
+func tagAt(offset int) feature {
+	return func(words []string, tags []string, index int) (string, bool) {
+		if index+offset < 0 || index+offset >= len(tags) {
+			return "", false
+		}
+		return tags[index+offset], true
+	}
+}
+
+func wordAt(offset int) feature {
+	return func(words []string, tags []string, index int) (string, bool) {
+		if index+offset < 0 || index+offset >= len(words) {
+			return "", false
+		}
+		return strings.ToLower(words[index+offset]), true
+	}
+}
+
+func suffix(length int) feature {
+	return func(words []string, tags []string, index int) (string, bool) {
+		runes := []rune(strings.ToLower(words[index]))
+		if len(runes) <= length {
+			return "", false
+		}
+		return string(runes[len(runes)-length:]), true
+	}
+}
+
+// Rule changes the tag From to To where the feature of Template is Value.
+// Score is how many more tags it fixed than it broke in training.
+type Rule struct {
+	Template string `json:"template"`
+	Value    string `json:"value"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Score    int    `json:"score"`
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s -> %s if %s is %q", r.From, r.To, r.Template, r.Value)
+}
+
+// Whether the rule changes the tag at index
+func (r Rule) applies(f feature, words []string, tags []string, index int) bool {
+	if tags[index] != r.From {
+		return false
+	}
+	value, ok := f(words, tags, index)
+	return ok && value == r.Value
+}
+
+// Apply changes the tags the rule applies to. Every position is checked
+// against the tags as they were before the rule, so a change does not
+// trigger the rule again further along.
+func (r Rule) Apply(words []string, tags []string) {
+	f := templates[r.Template]
+	if f == nil {
+		return
+	}
+	var changes []int
+	for index := range tags {
+		if r.applies(f, words, tags, index) {
+			changes = append(changes, index)
+		}
+	}
+	for _, index := range changes {
+		tags[index] = r.To
+	}
+}
+
+// Tagger tags with an initial tagger and then applies its rules in order
+type Tagger struct {
+	initial tagger.SequenceTagger
+	rules   []Rule
+}
+
+// New returns a Tagger from an initial tagger and rules learned before
+func New(initial tagger.SequenceTagger, rules []Rule) (*Tagger, error) {
+	for _, rule := range rules {
+		if templates[rule.Template] == nil {
+			return nil, ErrUnknownTemplate
+		}
+	}
+	return &Tagger{initial: initial, rules: append([]Rule(nil), rules...)}, nil
+}
+
+// Rules returns the rules in the order they are applied
+func (t *Tagger) Rules() []Rule {
+	return append([]Rule(nil), t.rules...)
+}
+
+// TagWords tags the words with the initial tagger and fixes the tags with
+// every rule in turn
+func (t *Tagger) TagWords(words []string) []string {
+	tags := t.initial.TagWords(words)
+	for _, rule := range t.rules {
+		rule.Apply(words, tags)
+	}
+	return tags
+}
+
+// Known reports whether the initial tagger knows the word, when it can tell
+func (t *Tagger) Known(word string) bool {
+	if known, ok := t.initial.(interface{ Known(string) bool }); ok {
+		return known.Known(word)
+	}
+	return false
+}
+
+// WriteRules writes the rules as JSON
+func (t *Tagger) WriteRules(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t.rules)
+}
+
+// ReadRules reads rules written by WriteRules, to give to New
+func ReadRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if templates[rule.Template] == nil {
+			return nil, ErrUnknownTemplate
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,79 @@
+package brill
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modquiz/go-nltb/lib/sequential"
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// "to" is followed by a verb but a unigram tagger tags walk as a noun
+const testCorpus = "I/ppss want/vb to/to walk/vb ./.\n" +
+	"The/at walk/nn was/bedz long/jj ./.\n" +
+	"A/at walk/nn is/bez good/jj ./.\n" +
+	"We/ppss like/vb to/to walk/vb ./.\n" +
+	"His/pp$ walk/nn ended/vbd ./.\n" +
+	"They/ppss went/vbd to/to run/vb ./.\n" +
+	"The/at run/nn ended/vbd ./.\n" +
+	"We/ppss took/vbd a/at walk/nn ./.\n" +
+	"It/pps was/bedz a/at run/nn ./.\n" +
+	"A/at run/nn is/bez fun/jj ./.\n"
+
+func initialTagger(t *testing.T) tagger.SequenceTagger {
+	unigram, err := sequential.TrainUnigram(tagger.NewSentenceReader(strings.NewReader(testCorpus)), sequential.NewDefault("nn"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return unigram
+}
+
+func TestTrain(t *testing.T) {
+	initial := initialTagger(t)
+	words := []string{"They", "want", "to", "walk", "."}
+	if got := initial.TagWords(words); got[3] != "nn" {
+		t.Fatalf("the initial tagger already tags %v", got)
+	}
+
+	brill, err := Train(initial, tagger.NewSentenceReader(strings.NewReader(testCorpus)), WithMinScore(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := brill.Rules()
+	if len(rules) == 0 || rules[0].From != "nn" || rules[0].To != "vb" || rules[0].Score < 2 {
+		t.Fatalf("Train() rules = %v", rules)
+	}
+	if got, want := brill.TagWords(words), []string{"ppss", "vb", "to", "vb", "."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tagger.TagWords() = %v, want %v", got, want)
+	}
+	if got := brill.TagWords([]string{"The", "walk", "."}); got[1] != "nn" {
+		t.Errorf("Tagger.TagWords() changed a correct tag: %v", got)
+	}
+
+	var saved bytes.Buffer
+	if err := brill.WriteRules(&saved); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadRules(&saved)
+	if err != nil || !reflect.DeepEqual(loaded, rules) {
+		t.Errorf("ReadRules() = %v, %v, want %v", loaded, err, rules)
+	}
+}
+
+func TestRule_Apply(t *testing.T) {
+	rule := Rule{Template: PrevTag, Value: "nn", From: "nn", To: "vb"}
+	tags := []string{"nn", "nn", "nn"}
+	rule.Apply([]string{"a", "b", "c"}, tags)
+	// every position sees the tags from before the rule
+	if want := []string{"nn", "vb", "vb"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Rule.Apply() = %v, want %v", tags, want)
+	}
+	if got := rule.String(); got != `nn -> vb if prev-tag is "nn"` {
+		t.Errorf("Rule.String() = %v", got)
+	}
+	if _, err := New(nil, []Rule{{Template: "nope"}}); err != ErrUnknownTemplate {
+		t.Errorf("New() error = %v, want %v", err, ErrUnknownTemplate)
+	}
+}
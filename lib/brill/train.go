@@ -0,0 +1,164 @@
+package brill
+
+import (
+	"io"
+	"sort"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// Option configures the training of a Tagger
+type Option func(*options)
+
+type options struct {
+	templates []string
+	maxRules  int
+	minScore  int
+}
+
+// WithTemplates sets the templates rules are made from
+func WithTemplates(names ...string) Option {
+	return func(o *options) {
+		o.templates = names
+	}
+}
+
+// WithMaxRules sets how many rules are learned at most, 100 by default
+func WithMaxRules(maxRules int) Option {
+	return func(o *options) {
+		o.maxRules = maxRules
+	}
+}
+
+// WithMinScore sets how many more tags a rule must fix than it breaks to be
+// learned, 2 by default
+func WithMinScore(minScore int) Option {
+	return func(o *options) {
+		o.minScore = minScore
+	}
+}
+
+// A sentence of the training corpus with the gold tags and the tags as
+// they are with the rules learned so far
+type trainingSentence struct {
+	words   []string
+	gold    []string
+	current []string
+}
+
+// A position in the training corpus
+type position struct {
+	sentence int
+	index    int
+}
+
+// Train tags the sentences with the initial tagger and learns rules one at
+// a time, each time the rule that fixes the most tags. The sentences are
+// held in memory.
+func Train(initial tagger.SequenceTagger, sentences tagger.SentenceReader, opts ...Option) (*Tagger, error) {
+	o := options{templates: DefaultTemplates, maxRules: 100, minScore: 2}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for _, name := range o.templates {
+		if templates[name] == nil {
+			return nil, ErrUnknownTemplate
+		}
+	}
+
+	var corpus []trainingSentence
+	for {
+		sentence, err := sentences.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		s := trainingSentence{words: make([]string, len(sentence)), gold: make([]string, len(sentence))}
+		for i := range sentence {
+			s.words[i], s.gold[i] = sentence[i].Word, sentence[i].Tag
+		}
+		s.current = initial.TagWords(s.words)
+		corpus = append(corpus, s)
+	}
+	if len(corpus) == 0 {
+		return nil, tagger.ErrNoTrainingData
+	}
+
+	t := &Tagger{initial: initial}
+	for len(t.rules) < o.maxRules {
+		rule, ok := bestRule(corpus, o.templates, o.minScore)
+		if !ok {
+			break
+		}
+		for _, s := range corpus {
+			rule.Apply(s.words, s.current)
+		}
+		t.rules = append(t.rules, rule)
+	}
+	return t, nil
+}
+
+// Finds the rule with the highest score. Candidates are the rules that fix
+// at least one mistake, tried from the one fixing the most, and a candidate
+// is dropped as soon as the tags it breaks put it below the best so far.
+func bestRule(corpus []trainingSentence, names []string, minScore int) (Rule, bool) {
+	fixes := make(map[Rule]int)
+	for _, s := range corpus {
+		for index := range s.current {
+			if s.current[index] == s.gold[index] {
+				continue
+			}
+			for _, name := range names {
+				if value, ok := templates[name](s.words, s.current, index); ok {
+					fixes[Rule{Template: name, Value: value, From: s.current[index], To: s.gold[index]}]++
+				}
+			}
+		}
+	}
+	candidates := make([]Rule, 0, len(fixes))
+	for candidate, count := range fixes {
+		candidate.Score = count
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.String() < b.String()
+	})
+
+	// the positions of every tag, only they can be changed by rules from it
+	byTag := make(map[string][]position)
+	for i, s := range corpus {
+		for index, tag := range s.current {
+			byTag[tag] = append(byTag[tag], position{sentence: i, index: index})
+		}
+	}
+
+	best, found := Rule{}, false
+	bestScore := minScore - 1
+	for _, candidate := range candidates {
+		if candidate.Score <= bestScore {
+			break
+		}
+		f := templates[candidate.Template]
+		score := candidate.Score
+		for _, pos := range byTag[candidate.From] {
+			s := corpus[pos.sentence]
+			if s.current[pos.index] == s.gold[pos.index] && candidate.applies(f, s.words, s.current, pos.index) {
+				score--
+				if score <= bestScore {
+					break
+				}
+			}
+		}
+		if score > bestScore {
+			candidate.Score = score
+			best, bestScore, found = candidate, score, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,158 @@
+// Package brown reads the Brown Corpus the way NLTK's nltk.corpus.brown does:
+// as words, sentences or paragraphs, with or without their tags, selected by
+// file and by category. The files are streamed, only one is open at a time
+// and only one paragraph of it is held in memory.
+package brown
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	data "github.com/modquiz/go-nltb/brown"
+)
+
+// The category of every file, by the first two letters of its file id
+var categories = map[string]string{
+	"ca": "news",
+	"cb": "editorial",
+	"cc": "reviews",
+	"cd": "religion",
+	"ce": "hobbies",
+	"cf": "lore",
+	"cg": "belles_lettres",
+	"ch": "government",
+	"cj": "learned",
+	"ck": "fiction",
+	"cl": "mystery",
+	"cm": "science_fiction",
+	"cn": "adventure",
+	"cp": "romance",
+	"cr": "humor",
+}
+
+// Category returns the category of a file id, or "" for files outside the
+// categorized corpus
+func Category(fileid string) string {
+	if len(fileid) < 2 {
+		return ""
+	}
+	return categories[fileid[:2]]
+}
+
+// Filter selects the files to read. A file is read when it is one of the
+// FileIDs and in one of the Categories, an empty field selects everything.
+type Filter struct {
+	FileIDs    []string
+	Categories []string
+}
+
+// Reader reads a copy of the Brown Corpus
+type Reader struct {
+	fsys    fs.FS
+	fileids []string
+}
+
+// New returns a Reader for the Brown Corpus embedded in the package
+func New() *Reader {
+	reader, _ := NewFS(data.Files)
+	return reader
+}
+
+// NewFS returns a Reader for the Brown Corpus files at the root of fsys,
+// e.g. os.DirFS of the brown directory of NLTK's data
+func NewFS(fsys fs.FS) (*Reader, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	fileids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || path.Ext(name) != "" || strings.ToUpper(name) == name {
+			continue // README, CONTENTS, cats.txt and the like
+		}
+		fileids = append(fileids, name)
+	}
+	sort.Strings(fileids)
+	return &Reader{fsys: fsys, fileids: fileids}, nil
+}
+
+// FileIDs returns the file ids of the given categories, every file id when
+// none are given
+func (r *Reader) FileIDs(categories ...string) []string {
+	return r.selected(Filter{Categories: categories})
+}
+
+// Categories returns the categories of the given files in alphabetical
+// order, the categories of every file when none are given
+func (r *Reader) Categories(fileids ...string) []string {
+	seen := make(map[string]bool)
+	for _, fileid := range r.selected(Filter{FileIDs: fileids}) {
+		if category := Category(fileid); category != "" {
+			seen[category] = true
+		}
+	}
+	found := make([]string, 0, len(seen))
+	for category := range seen {
+		found = append(found, category)
+	}
+	sort.Strings(found)
+	return found
+}
+
+// The file ids that pass the filter, in corpus order
+func (r *Reader) selected(filter Filter) []string {
+	wantFile := make(map[string]bool, len(filter.FileIDs))
+	for _, fileid := range filter.FileIDs {
+		wantFile[fileid] = true
+	}
+	wantCategory := make(map[string]bool, len(filter.Categories))
+	for _, category := range filter.Categories {
+		wantCategory[category] = true
+	}
+
+	selected := make([]string, 0)
+	for _, fileid := range r.fileids {
+		if len(wantFile) > 0 && !wantFile[fileid] {
+			continue
+		}
+		if len(wantCategory) > 0 && !wantCategory[Category(fileid)] {
+			continue
+		}
+		selected = append(selected, fileid)
+	}
+	return selected
+}
+
+// Words streams the words of the selected files
+func (r *Reader) Words(filter Filter) *WordIterator {
+	return &WordIterator{words: r.TaggedWords(filter)}
+}
+
+// TaggedWords streams the words of the selected files with their tags
+func (r *Reader) TaggedWords(filter Filter) *TaggedWordIterator {
+	return &TaggedWordIterator{sents: r.TaggedSents(filter)}
+}
+
+// Sents streams the sentences of the selected files
+func (r *Reader) Sents(filter Filter) *SentIterator {
+	return &SentIterator{sents: r.TaggedSents(filter)}
+}
+
+// TaggedSents streams the tagged sentences of the selected files. The
+// iterator is a tagger.SentenceReader so a tagger can be trained on it.
+func (r *Reader) TaggedSents(filter Filter) *TaggedSentIterator {
+	return &TaggedSentIterator{paras: r.TaggedParas(filter)}
+}
+
+// Paras streams the paragraphs of the selected files
+func (r *Reader) Paras(filter Filter) *ParaIterator {
+	return &ParaIterator{paras: r.TaggedParas(filter)}
+}
+
+// TaggedParas streams the tagged paragraphs of the selected files
+func (r *Reader) TaggedParas(filter Filter) *TaggedParaIterator {
+	return &TaggedParaIterator{fsys: r.fsys, fileids: r.selected(filter)}
+}
@@ -0,0 +1,64 @@
+package brown
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+func TestReader_Categories(t *testing.T) {
+	reader := New()
+	if got := len(reader.Categories()); got != 15 {
+		t.Errorf("Reader.Categories() = %v categories, want 15", got)
+	}
+	if got := reader.Categories("ca01", "cr09"); !reflect.DeepEqual(got, []string{"humor", "news"}) {
+		t.Errorf("Reader.Categories(ca01, cr09) = %v", got)
+	}
+	if got := len(reader.FileIDs("news")); got != 44 {
+		t.Errorf("Reader.FileIDs(news) = %v files, want 44", got)
+	}
+}
+
+func TestReader_TaggedSents(t *testing.T) {
+	var sents tagger.SentenceReader = New().TaggedSents(Filter{FileIDs: []string{"ca01"}})
+	first, err := sents.Next()
+	if err != nil {
+		t.Fatalf("TaggedSentIterator.Next() error = %v", err)
+	}
+	want := tagger.TaggedWord{Word: "Fulton", Tag: "np-tl"}
+	if len(first) < 2 || first[1] != want {
+		t.Errorf("TaggedSentIterator.Next() = %v, want %v second", first, want)
+	}
+}
+
+func TestReader_Paras(t *testing.T) {
+	paras := New().Paras(Filter{FileIDs: []string{"ca01"}})
+	defer paras.Close()
+	count, sentences := 0, 0
+	for {
+		para, err := paras.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ParaIterator.Next() error = %v", err)
+		}
+		count++
+		sentences += len(para)
+	}
+	// the first file has 98 sentences in 67 blank line separated blocks
+	if count != 67 || sentences != 98 {
+		t.Errorf("Reader.Paras(ca01) = %v paragraphs, %v sentences", count, sentences)
+	}
+}
+
+func TestReader_Words(t *testing.T) {
+	words := New().Words(Filter{Categories: []string{"humor"}})
+	defer words.Close()
+	first, err := words.Next()
+	if err != nil || first != "It" {
+		t.Errorf("WordIterator.Next() = %v, %v, want It", first, err)
+	}
+}
@@ -0,0 +1,204 @@
+package brown
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// TaggedParaIterator streams tagged paragraphs. Paragraphs are separated by
+// blank lines and hold one sentence per line.
+type TaggedParaIterator struct {
+	fsys    fs.FS
+	fileids []string
+	file    fs.File
+	reader  *bufio.Reader
+	err     error
+}
+
+// Next returns the next paragraph, or io.EOF once every file has been read
+func (it *TaggedParaIterator) Next() ([][]tagger.TaggedWord, error) {
+	for it.err == nil {
+		if it.reader == nil {
+			if len(it.fileids) == 0 {
+				it.err = io.EOF
+				break
+			}
+			file, err := it.fsys.Open(it.fileids[0])
+			if err != nil {
+				it.err = err
+				break
+			}
+			it.fileids = it.fileids[1:]
+			it.file = file
+			it.reader = bufio.NewReader(file)
+		}
+
+		para, err := it.readPara()
+		if err == io.EOF {
+			it.file.Close()
+			it.file, it.reader = nil, nil
+		} else if err != nil {
+			it.Close()
+			it.err = err
+			break
+		}
+		if len(para) > 0 {
+			return para, nil
+		}
+	}
+	return nil, it.err
+}
+
+// Reads lines up to the next blank line, every other line is a sentence
+func (it *TaggedParaIterator) readPara() ([][]tagger.TaggedWord, error) {
+	para := make([][]tagger.TaggedWord, 0)
+	for {
+		line, err := it.reader.ReadString('\n')
+		if strings.TrimSpace(line) == "" {
+			if len(para) > 0 || err != nil {
+				return para, err
+			}
+			continue
+		}
+		if sentence := tagger.ParseTaggedSentence(line); len(sentence) > 0 {
+			para = append(para, sentence)
+		}
+		if err != nil {
+			return para, err
+		}
+	}
+}
+
+// Close stops the iteration early and closes the open file
+func (it *TaggedParaIterator) Close() error {
+	it.fileids = nil
+	it.err = io.EOF
+	if it.file != nil {
+		file := it.file
+		it.file, it.reader = nil, nil
+		return file.Close()
+	}
+	return nil
+}
+
+// ParaIterator streams paragraphs as sentences of plain words
+type ParaIterator struct {
+	paras *TaggedParaIterator
+}
+
+// Next returns the next paragraph, or io.EOF once every file has been read
+func (it *ParaIterator) Next() ([][]string, error) {
+	taggedPara, err := it.paras.Next()
+	if err != nil {
+		return nil, err
+	}
+	para := make([][]string, len(taggedPara))
+	for i, sentence := range taggedPara {
+		para[i] = words(sentence)
+	}
+	return para, nil
+}
+
+// Close stops the iteration early and closes the open file
+func (it *ParaIterator) Close() error {
+	return it.paras.Close()
+}
+
+// TaggedSentIterator streams tagged sentences, it is a tagger.SentenceReader
+type TaggedSentIterator struct {
+	paras   *TaggedParaIterator
+	pending [][]tagger.TaggedWord
+}
+
+// Next returns the next sentence, or io.EOF once every file has been read
+func (it *TaggedSentIterator) Next() ([]tagger.TaggedWord, error) {
+	for len(it.pending) == 0 {
+		para, err := it.paras.Next()
+		if err != nil {
+			return nil, err
+		}
+		it.pending = para
+	}
+	sentence := it.pending[0]
+	it.pending = it.pending[1:]
+	return sentence, nil
+}
+
+// Close stops the iteration early and closes the open file
+func (it *TaggedSentIterator) Close() error {
+	it.pending = nil
+	return it.paras.Close()
+}
+
+// SentIterator streams sentences of plain words
+type SentIterator struct {
+	sents *TaggedSentIterator
+}
+
+// Next returns the next sentence, or io.EOF once every file has been read
+func (it *SentIterator) Next() ([]string, error) {
+	sentence, err := it.sents.Next()
+	if err != nil {
+		return nil, err
+	}
+	return words(sentence), nil
+}
+
+// Close stops the iteration early and closes the open file
+func (it *SentIterator) Close() error {
+	return it.sents.Close()
+}
+
+// TaggedWordIterator streams tagged words
+type TaggedWordIterator struct {
+	sents   *TaggedSentIterator
+	pending []tagger.TaggedWord
+}
+
+// Next returns the next word, or io.EOF once every file has been read
+func (it *TaggedWordIterator) Next() (tagger.TaggedWord, error) {
+	for len(it.pending) == 0 {
+		sentence, err := it.sents.Next()
+		if err != nil {
+			return tagger.TaggedWord{}, err
+		}
+		it.pending = sentence
+	}
+	word := it.pending[0]
+	it.pending = it.pending[1:]
+	return word, nil
+}
+
+// Close stops the iteration early and closes the open file
+func (it *TaggedWordIterator) Close() error {
+	it.pending = nil
+	return it.sents.Close()
+}
+
+// WordIterator streams plain words
+type WordIterator struct {
+	words *TaggedWordIterator
+}
+
+// Next returns the next word, or io.EOF once every file has been read
+func (it *WordIterator) Next() (string, error) {
+	word, err := it.words.Next()
+	return word.Word, err
+}
+
+// Close stops the iteration early and closes the open file
+func (it *WordIterator) Close() error {
+	return it.words.Close()
+}
+
+func words(sentence []tagger.TaggedWord) []string {
+	plain := make([]string, len(sentence))
+	for i := range sentence {
+		plain[i] = sentence[i].Word
+	}
+	return plain
+}
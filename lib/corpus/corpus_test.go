@@ -0,0 +1,57 @@
+package corpus
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+func TestSplitFiles(t *testing.T) {
+	files := make([]string, 0)
+	for i := 20; i > 0; i-- {
+		files = append(files, fmt.Sprintf("ca%02d", i))
+	}
+	split := SplitFiles(files, 0.1, 0.2, 42)
+	if len(split.Train) != 14 || len(split.Dev) != 2 || len(split.Test) != 4 {
+		t.Errorf("SplitFiles() sizes = %v, %v, %v", len(split.Train), len(split.Dev), len(split.Test))
+	}
+	if again := SplitFiles(files, 0.1, 0.2, 42); !reflect.DeepEqual(split, again) {
+		t.Errorf("SplitFiles() = %v then %v, want the same split", split, again)
+	}
+	seen := make(map[string]bool)
+	for _, part := range [][]string{split.Train, split.Dev, split.Test} {
+		for _, file := range part {
+			if seen[file] {
+				t.Errorf("SplitFiles() %v is in two parts", file)
+			}
+			seen[file] = true
+		}
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	sentence := []tagger.TaggedWord{{Word: "the", Tag: "at"}, {Word: "dog", Tag: "nn"}, {Word: ".", Tag: "."}}
+	sentences := make([][]tagger.TaggedWord, 10)
+	for i := range sentences {
+		sentences[i] = sentence
+	}
+	folds, err := SentenceFolds(sentences, 5, 1)
+	if err != nil {
+		t.Fatalf("SentenceFolds() error = %v", err)
+	}
+	cv, err := CrossValidate(folds, HMMTrainer())
+	if err != nil {
+		t.Fatalf("CrossValidate() error = %v", err)
+	}
+	if len(cv.Folds) != 5 || cv.Total.Tokens != 30 {
+		t.Errorf("CrossValidate() folds = %v, tokens = %v", len(cv.Folds), cv.Total.Tokens)
+	}
+	if cv.MeanAccuracy() != 1 {
+		t.Errorf("CrossValidation.MeanAccuracy() = %v, want 1", cv.MeanAccuracy())
+	}
+	if _, err := SentenceFolds(sentences, 1, 1); err != ErrTooFewFolds {
+		t.Errorf("SentenceFolds() error = %v, want %v", err, ErrTooFewFolds)
+	}
+}
@@ -0,0 +1,186 @@
+package corpus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"sort"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// ErrTooFewFolds is returned when cross-validating with fewer than two folds
+// or more folds than there is data for
+var ErrTooFewFolds = errors.New("Cross-validation needs at least two non empty folds")
+
+// Fold is one round of cross-validation: the sentences to train on and the
+// held-out sentences to evaluate on
+type Fold struct {
+	Train tagger.SentenceReader
+	Test  tagger.SentenceReader
+}
+
+// TrainFunc builds a tagger from the sentences of a fold
+type TrainFunc func(sentences tagger.SentenceReader) (tagger.SequenceTagger, error)
+
+// HMMTrainer returns a TrainFunc that trains the HMM tagger with the options
+func HMMTrainer(options ...tagger.Option) TrainFunc {
+	return func(sentences tagger.SentenceReader) (tagger.SequenceTagger, error) {
+		return tagger.Train(sentences, options...)
+	}
+}
+
+// FileFolds deterministically deals the files of fsys out to k folds. Every
+// fold tests on its own files and trains on the files of every other fold.
+func FileFolds(fsys fs.FS, files []string, k int, seed int64) ([]Fold, error) {
+	if k < 2 || k > len(files) {
+		return nil, ErrTooFewFolds
+	}
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+
+	indexes := foldIndexes(len(sorted), k, seed)
+	folds := make([]Fold, k)
+	for i := range folds {
+		var train, test []string
+		for j, fold := range indexes {
+			for _, index := range fold {
+				if i == j {
+					test = append(test, sorted[index])
+				} else {
+					train = append(train, sorted[index])
+				}
+			}
+		}
+		sort.Strings(train)
+		folds[i] = Fold{
+			Train: tagger.NewFSSentenceReader(fsys, train...),
+			Test:  tagger.NewFSSentenceReader(fsys, test...),
+		}
+	}
+	return folds, nil
+}
+
+// SentenceFolds is FileFolds for sentences that are already in memory
+func SentenceFolds(sentences [][]tagger.TaggedWord, k int, seed int64) ([]Fold, error) {
+	if k < 2 || k > len(sentences) {
+		return nil, ErrTooFewFolds
+	}
+	indexes := foldIndexes(len(sentences), k, seed)
+	folds := make([]Fold, k)
+	for i := range folds {
+		var train, test [][]tagger.TaggedWord
+		inTest := make(map[int]bool, len(indexes[i]))
+		for _, index := range indexes[i] {
+			inTest[index] = true
+		}
+		for index, sentence := range sentences {
+			if inTest[index] {
+				test = append(test, sentence)
+			} else {
+				train = append(train, sentence)
+			}
+		}
+		folds[i] = Fold{
+			Train: tagger.NewSliceSentenceReader(train),
+			Test:  tagger.NewSliceSentenceReader(test),
+		}
+	}
+	return folds, nil
+}
+
+// CrossValidation holds the evaluation of every fold and of all of them
+// taken together
+type CrossValidation struct {
+	Folds []*tagger.Evaluation
+	Total *tagger.Evaluation
+}
+
+// CrossValidate trains a tagger on every fold and evaluates it on the
+// fold's held-out sentences
+func CrossValidate(folds []Fold, train TrainFunc) (*CrossValidation, error) {
+	if len(folds) < 2 {
+		return nil, ErrTooFewFolds
+	}
+	cv := &CrossValidation{Total: tagger.NewEvaluation()}
+	for i, fold := range folds {
+		foldTagger, err := train(fold.Train)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d: %v", i+1, err)
+		}
+		eval, err := tagger.Evaluate(foldTagger, fold.Test)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d: %v", i+1, err)
+		}
+		cv.Folds = append(cv.Folds, eval)
+		cv.Total.Merge(eval)
+	}
+	return cv, nil
+}
+
+// MeanAccuracy is the average accuracy of the folds
+func (cv *CrossValidation) MeanAccuracy() float64 {
+	var sum float64
+	for _, eval := range cv.Folds {
+		sum += eval.Accuracy()
+	}
+	return sum / float64(len(cv.Folds))
+}
+
+// StdDevAccuracy is the standard deviation of the accuracy of the folds
+func (cv *CrossValidation) StdDevAccuracy() float64 {
+	mean := cv.MeanAccuracy()
+	var sum float64
+	for _, eval := range cv.Folds {
+		sum += (eval.Accuracy() - mean) * (eval.Accuracy() - mean)
+	}
+	return math.Sqrt(sum / float64(len(cv.Folds)))
+}
+
+// WriteText writes the accuracy of every fold followed by the report of all
+// folds taken together
+func (cv *CrossValidation) WriteText(w io.Writer, maxConfusions int) error {
+	for i, eval := range cv.Folds {
+		fmt.Fprintf(w, "fold %d: accuracy %.4f, known %.4f, unknown %.4f (%d tokens)\n",
+			i+1, eval.Accuracy(), eval.KnownAccuracy(), eval.UnknownAccuracy(), eval.Tokens)
+	}
+	fmt.Fprintf(w, "mean accuracy %.4f, standard deviation %.4f\n\n", cv.MeanAccuracy(), cv.StdDevAccuracy())
+	return cv.Total.WriteText(w, maxConfusions)
+}
+
+// WriteJSON writes the accuracies of every fold and the full report of all
+// folds taken together
+func (cv *CrossValidation) WriteJSON(w io.Writer) error {
+	type foldReport struct {
+		Tokens          int     `json:"tokens"`
+		Accuracy        float64 `json:"accuracy"`
+		KnownAccuracy   float64 `json:"known_accuracy"`
+		UnknownAccuracy float64 `json:"unknown_accuracy"`
+	}
+	report := struct {
+		Folds          []foldReport    `json:"folds"`
+		MeanAccuracy   float64         `json:"mean_accuracy"`
+		StdDevAccuracy float64         `json:"stddev_accuracy"`
+		Total          json.RawMessage `json:"total"`
+	}{
+		MeanAccuracy:   cv.MeanAccuracy(),
+		StdDevAccuracy: cv.StdDevAccuracy(),
+	}
+	for _, eval := range cv.Folds {
+		report.Folds = append(report.Folds, foldReport{eval.Tokens, eval.Accuracy(), eval.KnownAccuracy(), eval.UnknownAccuracy()})
+	}
+
+	var total bytes.Buffer
+	if err := cv.Total.WriteJSON(&total); err != nil {
+		return err
+	}
+	report.Total = json.RawMessage(total.Bytes())
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
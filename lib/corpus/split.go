@@ -0,0 +1,119 @@
+// Package corpus holds utilities for working with tagged corpora: splitting
+// them into train, dev and test parts and cross-validating taggers on them.
+package corpus
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// FileSplit is a corpus split by file
+type FileSplit struct {
+	Train []string
+	Dev   []string
+	Test  []string
+}
+
+// SentenceSplit is a corpus split by sentence
+type SentenceSplit struct {
+	Train [][]tagger.TaggedWord
+	Dev   [][]tagger.TaggedWord
+	Test  [][]tagger.TaggedWord
+}
+
+// Shuffles the indexes 0..n-1 in an order that only depends on the seed
+func shuffled(n int, seed int64) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	random := rand.New(rand.NewSource(seed))
+	random.Shuffle(n, func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+	return order
+}
+
+// Deals the shuffled indexes out to test, dev and train by the given
+// fractions. Each part is sorted again so it keeps the corpus order.
+func splitIndexes(n int, dev float64, test float64, seed int64) (train []int, devPart []int, testPart []int) {
+	order := shuffled(n, seed)
+	numTest := int(math.Round(test * float64(n)))
+	numDev := int(math.Round(dev * float64(n)))
+	if numTest > n {
+		numTest = n
+	}
+	if numTest+numDev > n {
+		numDev = n - numTest
+	}
+	testPart = append([]int(nil), order[:numTest]...)
+	devPart = append([]int(nil), order[numTest:numTest+numDev]...)
+	train = append([]int(nil), order[numTest+numDev:]...)
+	sort.Ints(testPart)
+	sort.Ints(devPart)
+	sort.Ints(train)
+	return train, devPart, testPart
+}
+
+// SplitFiles deterministically splits the files into train, dev and test
+// parts, dev and test being the fractions of the files held out (0.1 for
+// 10%). The same files and seed always give the same split.
+func SplitFiles(files []string, dev float64, test float64, seed int64) FileSplit {
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+	train, devPart, testPart := splitIndexes(len(sorted), dev, test, seed)
+	pick := func(indexes []int) []string {
+		picked := make([]string, len(indexes))
+		for i, index := range indexes {
+			picked[i] = sorted[index]
+		}
+		return picked
+	}
+	return FileSplit{Train: pick(train), Dev: pick(devPart), Test: pick(testPart)}
+}
+
+// SplitSentences is SplitFiles for single sentences, the sentences keep
+// their corpus order within every part
+func SplitSentences(sentences [][]tagger.TaggedWord, dev float64, test float64, seed int64) SentenceSplit {
+	train, devPart, testPart := splitIndexes(len(sentences), dev, test, seed)
+	pick := func(indexes []int) [][]tagger.TaggedWord {
+		picked := make([][]tagger.TaggedWord, len(indexes))
+		for i, index := range indexes {
+			picked[i] = sentences[index]
+		}
+		return picked
+	}
+	return SentenceSplit{Train: pick(train), Dev: pick(devPart), Test: pick(testPart)}
+}
+
+// ReadSentences reads every sentence of the reader into memory, for
+// splitting by sentence
+func ReadSentences(sentences tagger.SentenceReader) ([][]tagger.TaggedWord, error) {
+	all := make([][]tagger.TaggedWord, 0)
+	for {
+		sentence, err := sentences.Next()
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, sentence)
+	}
+}
+
+// Deals the shuffled indexes out to k folds in turn
+func foldIndexes(n int, k int, seed int64) [][]int {
+	folds := make([][]int, k)
+	for i, index := range shuffled(n, seed) {
+		folds[i%k] = append(folds[i%k], index)
+	}
+	for _, fold := range folds {
+		sort.Ints(fold)
+	}
+	return folds
+}
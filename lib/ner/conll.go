@@ -0,0 +1,80 @@
+package ner
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// ErrBadCoNLL is returned when a line of CoNLL data has no label
+var ErrBadCoNLL = errors.New("CoNLL line without a label")
+
+// reads CoNLL data one sentence at a time
+type conllReader struct {
+	scanner *bufio.Scanner
+}
+
+// NewCoNLLReader reads named entity data in the CoNLL format: one token per
+// line with the token first and its label last, and a blank line after every
+// sentence. -DOCSTART- lines are skipped. The Tag of every word is its label
+// in the BIO scheme Train expects, IOB1 labels like CoNLL 2003's and IOBES
+// labels are converted.
+func NewCoNLLReader(r io.Reader) tagger.SentenceReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &conllReader{scanner: scanner}
+}
+
+func (r *conllReader) Next() ([]tagger.TaggedWord, error) {
+	var sentence []tagger.TaggedWord
+	for r.scanner.Scan() {
+		fields := strings.Fields(r.scanner.Text())
+		if len(fields) == 0 {
+			if len(sentence) > 0 {
+				return sentence, nil
+			}
+			continue
+		}
+		if fields[0] == "-DOCSTART-" {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, ErrBadCoNLL
+		}
+		prev := outside
+		if len(sentence) > 0 {
+			prev = sentence[len(sentence)-1].Tag
+		}
+		sentence = append(sentence, tagger.TaggedWord{Word: fields[0], Tag: toBIO(fields[len(fields)-1], prev)})
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sentence) > 0 {
+		return sentence, nil
+	}
+	return nil, io.EOF
+}
+
+// Converts a label to BIO given the converted label before it: an I- label
+// that does not continue an entity of its type starts one, S- and E- are
+// the B- and I- of IOBES
+func toBIO(label string, prev string) string {
+	if len(label) < 2 || label[1] != '-' {
+		return label
+	}
+	entityType := label[2:]
+	switch label[0] {
+	case 'S':
+		return "B-" + entityType
+	case 'E':
+		label = "I-" + entityType
+	}
+	if label[0] == 'I' && prev != "B-"+entityType && prev != "I-"+entityType {
+		return "B-" + entityType
+	}
+	return label
+}
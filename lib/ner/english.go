@@ -0,0 +1,34 @@
+package ner
+
+import (
+	"bytes"
+	_ "embed"
+	"sync"
+)
+
+//go:generate go run ../../cmd/ner-train -out english.model
+
+// The Recognizer trained by cmd/ner-train on the Brown Corpus, labelled with
+// entities from its proper noun tags and the English gazetteer
+//
+//go:embed english.model
+var englishModel []byte
+
+var (
+	englishOnce       sync.Once
+	englishRecognizer *Recognizer
+)
+
+// English returns a Recognizer for English text that finds PERSON, LOCATION,
+// ORGANIZATION and MISC entities, the tags of MITIE's English model. It is
+// read the first time it is needed and shared after that.
+func English() *Recognizer {
+	englishOnce.Do(func() {
+		r, err := LoadRecognizer(bytes.NewReader(englishModel))
+		if err != nil {
+			panic("ner: the embedded English model is broken: " + err.Error())
+		}
+		englishRecognizer = r
+	})
+	return englishRecognizer
+}
@@ -0,0 +1,116 @@
+// Package ner finds named entities, the people, places and organisations a
+// text talks about. Extractor uses a MITIE model through cgo when built with
+// the mitie tag, and Recognizer is a pure Go averaged perceptron. Both take
+// tokens and return Entities.
+package ner
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrNotInText is returned by Locate when a token is not in the text
+	ErrNotInText = errors.New("Token not found in the text")
+	// ErrCantOpen is returned by NewExtractor when a language model file can't
+	// be loaded.
+	ErrCantOpen = errors.New("Unable to open model file")
+	// ErrMemory occurs when underlying C structs cannot be allocated.
+	ErrMemory = errors.New("Could not allocate memory")
+	// ErrBadRange is returned for an entity outside of the tokens.
+	ErrBadRange = errors.New("Entity range outside of the tokens")
+	// ErrOverlap is returned when the two entities of a relation overlap.
+	ErrOverlap = errors.New("Entities overlap")
+	// ErrIncompatibleModels is returned when a relation detector was trained
+	// with another NER model than the extractor's.
+	ErrIncompatibleModels = errors.New("Relation detector does not match the NER model")
+)
+
+// Range specifies the position of an Entity within a token slice.
+type Range struct {
+	Start int
+	End   int
+}
+
+// Entity is a detected entity. Name is its tokens joined with spaces, Text
+// and the offsets are only set once the entity is located in the text it
+// came from, by ExtractText or Locate. The ends are exclusive so text[ByteStart:ByteEnd] is
+// Text.
+type Entity struct {
+	Score     float64
+	Tag       int
+	TagString string
+	Name      string
+	Range     Range
+	Text      string
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+}
+
+// Relation is a binary relation found by a RelationDetector, from Arg1 to
+// Arg2, like a person and their place of birth. Name is the relation, e.g.
+// people.person.place_of_birth.
+type Relation struct {
+	Name  string
+	Score float64
+	Arg1  Entity
+	Arg2  Entity
+}
+
+// EntityExtractor is what Extractor and Recognizer have in common. Tag of
+// an Entity is an index into Tags.
+type EntityExtractor interface {
+	Tags() []string
+	Extract(tokens []string) ([]Entity, error)
+	// ExtractText splits the text into tokens the way the model expects and
+	// returns the entities located in the text
+	ExtractText(text string) ([]Entity, error)
+	Free()
+}
+
+var (
+	_ EntityExtractor = (*Extractor)(nil)
+	_ EntityExtractor = (*Recognizer)(nil)
+)
+
+// Locate finds the tokens in the text they were split from, in order, and
+// sets the Text and offsets of every entity from the tokens it covers. It
+// returns ErrNotInText for a token missing from the text and ErrBadRange for
+// an entity outside of the tokens.
+func Locate(text string, tokens []string, entities []Entity) error {
+	located := make([]Token, len(tokens))
+	position := 0
+	for i, token := range tokens {
+		offset := strings.Index(text[position:], token)
+		if offset < 0 {
+			return ErrNotInText
+		}
+		position += offset
+		located[i] = Token{Text: token, ByteStart: position, ByteEnd: position + len(token)}
+		position += len(token)
+	}
+	setRuneOffsets(text, located)
+	return locateTokens(text, located, entities)
+}
+
+// Sets the Text and offsets of every entity from the tokens it covers
+func locateTokens(text string, tokens []Token, entities []Entity) error {
+	for i := range entities {
+		entity := &entities[i]
+		if entity.Range.Start < 0 || entity.Range.Start >= entity.Range.End || entity.Range.End > len(tokens) {
+			return ErrBadRange
+		}
+		first, last := tokens[entity.Range.Start], tokens[entity.Range.End-1]
+		entity.ByteStart, entity.ByteEnd = first.ByteStart, last.ByteEnd
+		entity.RuneStart, entity.RuneEnd = first.RuneStart, last.RuneEnd
+		entity.Text = text[entity.ByteStart:entity.ByteEnd]
+	}
+	return nil
+}
+
+// Whether two ranges of tokens share a token
+func overlap(a Range, b Range) bool {
+	return a.Start < b.End && b.Start < a.End
+}
@@ -0,0 +1,142 @@
+package ner
+
+import (
+	"bufio"
+	"embed"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The lists of the English gazetteer, one name per line
+//
+//go:embed gazetteers/*.txt
+var gazetteerFiles embed.FS
+
+// The lists of the English gazetteer. The first four are names of their own,
+// the others are words found in or in front of a name.
+const (
+	PersonList       = "person" // first names
+	LocationList     = "location"
+	OrganizationList = "organization"
+	MiscList         = "misc" // nationalities, languages and religions
+	TitleList        = "title"
+	OrgWordList      = "orgword" // University, Company
+	LocWordList      = "locword" // County, River
+)
+
+// Gazetteer holds lists of names. Names are matched regardless of case and
+// can be several tokens long, like "new york".
+type Gazetteer struct {
+	// the lists every name is in
+	names map[string][]string
+	// the most tokens in a name
+	maxTokens int
+}
+
+// NewGazetteer returns an empty Gazetteer
+func NewGazetteer() *Gazetteer {
+	return &Gazetteer{names: make(map[string][]string)}
+}
+
+// EnglishGazetteer returns a copy of the gazetteer the English model is
+// trained with, to add names to for a model of your own
+func EnglishGazetteer() *Gazetteer {
+	g := NewGazetteer()
+	files, _ := gazetteerFiles.ReadDir("gazetteers")
+	for _, file := range files {
+		f, err := gazetteerFiles.Open("gazetteers/" + file.Name())
+		if err != nil {
+			continue
+		}
+		list := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				g.Add(list, line)
+			}
+		}
+		f.Close()
+	}
+	return g
+}
+
+// Add puts a name into a list, the tokens of the name separated by spaces
+func (g *Gazetteer) Add(list string, name string) {
+	tokens := strings.Fields(strings.ToLower(name))
+	if len(tokens) == 0 {
+		return
+	}
+	key := strings.Join(tokens, " ")
+	for _, l := range g.names[key] {
+		if l == list {
+			return
+		}
+	}
+	g.names[key] = append(g.names[key], list)
+	if len(tokens) > g.maxTokens {
+		g.maxTokens = len(tokens)
+	}
+}
+
+// Contains reports whether the name made of tokens is in the list
+func (g *Gazetteer) Contains(list string, tokens ...string) bool {
+	for _, l := range g.names[strings.ToLower(strings.Join(tokens, " "))] {
+		if l == list {
+			return true
+		}
+	}
+	return false
+}
+
+// Lists returns the name of every list, sorted
+func (g *Gazetteer) Lists() []string {
+	seen := make(map[string]bool)
+	var lists []string
+	for _, names := range g.names {
+		for _, list := range names {
+			if !seen[list] {
+				seen[list] = true
+				lists = append(lists, list)
+			}
+		}
+	}
+	sort.Strings(lists)
+	return lists
+}
+
+// Names returns the names in a list, sorted
+func (g *Gazetteer) Names(list string) []string {
+	var names []string
+	for name, lists := range g.names {
+		for _, l := range lists {
+			if l == list {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Match returns for every token the lists of the names covering it, as
+// "list B" on the first token of a name and "list I" on the others
+func (g *Gazetteer) Match(tokens []string) [][]string {
+	matches := make([][]string, len(tokens))
+	lower := make([]string, len(tokens))
+	for i := range tokens {
+		lower[i] = strings.ToLower(tokens[i])
+	}
+	for i := range tokens {
+		for n := 1; n <= g.maxTokens && i+n <= len(tokens); n++ {
+			lists := g.names[strings.Join(lower[i:i+n], " ")]
+			for _, list := range lists {
+				matches[i] = append(matches[i], list+" B")
+				for j := i + 1; j < i+n; j++ {
+					matches[j] = append(matches[j], list+" I")
+				}
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,133 @@
+package ner
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+	"sort"
+)
+
+// ModelVersion is the version of the model format written by
+// Recognizer.Save. LoadRecognizer refuses models of any other version.
+const ModelVersion = 1
+
+var (
+	// ErrBadModel is returned by LoadRecognizer when the data is not a
+	// Recognizer model
+	ErrBadModel = errors.New("Not a named entity model")
+	// ErrModelVersion is returned by LoadRecognizer for models of an
+	// unsupported version
+	ErrModelVersion = errors.New("Unsupported named entity model version")
+)
+
+// The gob encoded form of a Recognizer, features and names are sorted so the
+// same model always produces the same bytes
+type savedModel struct {
+	Magic     string
+	Version   int
+	Labels    []string
+	Features  []string
+	Weights   [][]classWeight
+	Gazetteer []savedList
+}
+
+// A list of the gazetteer and its names
+type savedList struct {
+	List  string
+	Names []string
+}
+
+const modelMagic = "NLTBNER"
+
+// Save writes the trained model, with its gazetteer, to w for LoadRecognizer
+// to read back
+func (r *Recognizer) Save(w io.Writer) error {
+	model := savedModel{
+		Magic:    modelMagic,
+		Version:  ModelVersion,
+		Labels:   r.labels,
+		Features: make([]string, 0, len(r.features)),
+	}
+	for feature := range r.features {
+		model.Features = append(model.Features, feature)
+	}
+	sort.Strings(model.Features)
+	model.Weights = make([][]classWeight, len(model.Features))
+	for i, feature := range model.Features {
+		model.Weights[i] = r.weights[r.features[feature]]
+	}
+	if r.gazetteer != nil {
+		for _, list := range r.gazetteer.Lists() {
+			model.Gazetteer = append(model.Gazetteer, savedList{List: list, Names: r.gazetteer.Names(list)})
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := gob.NewEncoder(bw).Encode(&model); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// SaveFile writes the model to the file at path, see Save
+func (r *Recognizer) SaveFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadRecognizer reads a model written by Recognizer.Save
+func LoadRecognizer(rd io.Reader) (*Recognizer, error) {
+	var model savedModel
+	if err := gob.NewDecoder(bufio.NewReader(rd)).Decode(&model); err != nil || model.Magic != modelMagic {
+		return nil, ErrBadModel
+	}
+	if model.Version != ModelVersion {
+		return nil, ErrModelVersion
+	}
+	if len(model.Weights) != len(model.Features) {
+		return nil, ErrBadModel
+	}
+	for _, label := range model.Labels {
+		if label != outside && (len(label) < 3 || label[1] != '-') {
+			return nil, ErrBadModel
+		}
+	}
+
+	gazetteer := NewGazetteer()
+	for _, list := range model.Gazetteer {
+		for _, name := range list.Names {
+			gazetteer.Add(list.List, name)
+		}
+	}
+	r := newRecognizer(model.Labels, gazetteer)
+	r.weights = model.Weights
+	for i, feature := range model.Features {
+		r.features[feature] = i
+		for _, weight := range model.Weights[i] {
+			if weight.Class < 0 || weight.Class >= len(r.labels) {
+				return nil, ErrBadModel
+			}
+		}
+	}
+	return r, nil
+}
+
+// LoadRecognizerFile reads the model in the file at path, see
+// LoadRecognizer
+func LoadRecognizerFile(path string) (*Recognizer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadRecognizer(file)
+}
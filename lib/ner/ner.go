@@ -1,3 +1,5 @@
+//go:build mitie
+
 package ner
 
 /*
@@ -22,19 +24,10 @@ static void ner_arr_free(char **a, int size) {
 import "C"
 
 import (
-	"errors"
 	"strings"
 	"unsafe"
 )
 
-var (
-	// ErrCantOpen is returned by NewExtractor when a language model file can't
-	// be loaded.
-	ErrCantOpen = errors.New("Unable to open model file")
-	// ErrMemory occurs when underlying C structs cannot be allocated.
-	ErrMemory = errors.New("Could not allocate memory")
-)
-
 // Tokenize returns a slice that contains a tokenized copy of the input text.
 func Tokenize(text string) []string {
 	cs := C.CString(text)
@@ -52,22 +45,47 @@ func Tokenize(text string) []string {
 	return tokens
 }
 
-// Range specifies the position of an Entity within a token slice.
-type Range struct {
-	Start int
-	End   int
+// TokenizeWithOffsets is Tokenize with the location of every token in the
+// text, from mitie_tokenize_with_offsets.
+func TokenizeWithOffsets(text string) []Token {
+	cs := C.CString(text)
+	defer C.free(unsafe.Pointer(cs))
+	var coffsets *C.ulong
+	ctokens := C.mitie_tokenize_with_offsets(cs, &coffsets)
+	defer C.mitie_free(unsafe.Pointer(ctokens))
+	defer C.mitie_free(unsafe.Pointer(coffsets))
+	p := (*[1 << 30]*C.char)(unsafe.Pointer(ctokens))
+	offsets := (*[1 << 30]C.ulong)(unsafe.Pointer(coffsets))
+	tokens := make([]Token, 0, 20)
+	for i := 0; p[i] != nil; i++ {
+		word := C.GoString(p[i])
+		start := int(offsets[i])
+		end := start + len(word)
+		if end > len(text) {
+			end = len(text)
+		}
+		tokens = append(tokens, Token{Text: word, ByteStart: start, ByteEnd: end})
+	}
+	setRuneOffsets(text, tokens)
+	return tokens
+}
+
+// A NULL terminated copy of the tokens in C memory, for freeCTokens to
+// release.
+func makeCTokens(tokens []string) **C.char {
+	ctokens := C.ner_arr_make(C.int(len(tokens)) + 1) // NULL termination
+	for i, t := range tokens {
+		cs := C.CString(t) // released by ner_arr_free
+		C.ner_arr_set(ctokens, cs, C.int(i))
+	}
+	return ctokens
 }
 
-// Entity is a detected entity.
-type Entity struct {
-	Score     float64
-	Tag       int
-	TagString string
-	Name      string
-	Range     Range
+func freeCTokens(ctokens **C.char, n int) {
+	C.ner_arr_free(ctokens, C.int(n)+1)
 }
 
-// Extractor detects entities based on a language model file.
+// Extractor detects entities based on a MITIE language model file.
 type Extractor struct {
 	ner *C.mitie_named_entity_extractor
 }
@@ -107,14 +125,10 @@ func (ext *Extractor) tagString(index int) string {
 }
 
 // Extract runs the extractor and returns a slice of Entities found in the
-// given tokens.
+// given tokens. Locate finds them in the text the tokens came from.
 func (ext *Extractor) Extract(tokens []string) ([]Entity, error) {
-	ctokens := C.ner_arr_make(C.int(len(tokens)) + 1) // NULL termination
-	defer C.ner_arr_free(ctokens, C.int(len(tokens))+1)
-	for i, t := range tokens {
-		cs := C.CString(t) // released by ner_arr_free
-		C.ner_arr_set(ctokens, cs, C.int(i))
-	}
+	ctokens := makeCTokens(tokens)
+	defer freeCTokens(ctokens, len(tokens))
 
 	dets := C.mitie_extract_entities(ext.ner, ctokens)
 	defer C.mitie_free(unsafe.Pointer(dets))
@@ -129,12 +143,20 @@ func (ext *Extractor) Extract(tokens []string) ([]Entity, error) {
 		pos := int(C.mitie_ner_get_detection_position(dets, C.ulong(i)))
 		len := int(C.mitie_ner_get_detection_length(dets, C.ulong(i)))
 
+		tag := int(C.mitie_ner_get_detection_tag(dets, C.ulong(i)))
 		entities[i] = Entity{
-			Tag:   int(C.mitie_ner_get_detection_tag(dets, C.ulong(i))),
-			Score: float64(C.mitie_ner_get_detection_score(dets, C.ulong(i))),
-			Name:  strings.Join(tokens[pos:pos+len], " "),
-			Range: Range{pos, pos + len},
+			Tag:       tag,
+			TagString: ext.tagString(tag),
+			Score:     float64(C.mitie_ner_get_detection_score(dets, C.ulong(i))),
+			Name:      strings.Join(tokens[pos:pos+len], " "),
+			Range:     Range{pos, pos + len},
 		}
 	}
 	return entities, nil
 }
+
+// ExtractText returns the entities of the text, split into tokens by
+// TokenizeWithOffsets as MITIE models expect.
+func (ext *Extractor) ExtractText(text string) ([]Entity, error) {
+	return extractTokens(ext, text, TokenizeWithOffsets(text))
+}
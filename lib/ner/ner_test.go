@@ -0,0 +1,267 @@
+package ner
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+func readAll(t *testing.T, reader tagger.SentenceReader) [][]tagger.TaggedWord {
+	var sentences [][]tagger.TaggedWord
+	for {
+		sentence, err := reader.Next()
+		if err == io.EOF {
+			return sentences
+		}
+		if err != nil {
+			t.Fatalf("SentenceReader.Next() error = %v", err)
+		}
+		sentences = append(sentences, sentence)
+	}
+}
+
+func TestNewCoNLLReader(t *testing.T) {
+	// CoNLL 2003 uses IOB1, where an entity only starts with B- after another
+	// entity of the same type
+	data := "-DOCSTART- -X- O O\n\nEU NNP I-NP I-ORG\nrejects VBZ I-VP O\nGerman JJ I-NP I-MISC\ncall NN I-NP O\n\n" +
+		"Peter NNP I-NP I-PER\nBlackburn NNP I-NP I-PER\nKarl NNP I-NP B-PER\n"
+	got := readAll(t, NewCoNLLReader(strings.NewReader(data)))
+	want := [][]tagger.TaggedWord{
+		{{Word: "EU", Tag: "B-ORG"}, {Word: "rejects", Tag: "O"}, {Word: "German", Tag: "B-MISC"}, {Word: "call", Tag: "O"}},
+		{{Word: "Peter", Tag: "B-PER"}, {Word: "Blackburn", Tag: "I-PER"}, {Word: "Karl", Tag: "B-PER"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewCoNLLReader() = %v, want %v", got, want)
+	}
+	if _, err := NewCoNLLReader(strings.NewReader("EU\n")).Next(); err != ErrBadCoNLL {
+		t.Errorf("NewCoNLLReader() error = %v, want %v", err, ErrBadCoNLL)
+	}
+}
+
+func TestGazetteer_Match(t *testing.T) {
+	g := NewGazetteer()
+	g.Add(LocationList, "New York")
+	g.Add(LocationList, "York")
+	g.Add(TitleList, "mr.")
+	got := g.Match([]string{"Mr.", "Smith", "left", "new", "York"})
+	want := [][]string{{"title B"}, nil, nil, {"location B"}, {"location I", "location B"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Gazetteer.Match() = %v, want %v", got, want)
+	}
+	if !EnglishGazetteer().Contains(LocationList, "Los", "Angeles") {
+		t.Errorf("EnglishGazetteer() has no Los Angeles")
+	}
+}
+
+const testCorpus = "Mr. O\nSmith B-PERSON\nlives O\nin O\nParis B-LOCATION\n. O\n\n" +
+	"Mrs. O\nJones B-PERSON\nworks O\nfor O\nAcme B-ORGANIZATION\nCorporation I-ORGANIZATION\n. O\n\n" +
+	"Dr. O\nBrown B-PERSON\nmoved O\nto O\nLondon B-LOCATION\n. O\n\n" +
+	"The O\nstore O\nwas O\nclosed O\n. O\n"
+
+func TestTrain(t *testing.T) {
+	recognizer, err := Train(NewCoNLLReader(strings.NewReader(testCorpus)), WithGazetteer(NewGazetteer()), WithIterations(10))
+	if err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+	if got, want := recognizer.Tags(), []string{"LOCATION", "ORGANIZATION", "PERSON"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Recognizer.Tags() = %v, want %v", got, want)
+	}
+	tokens := []string{"Mr.", "Jones", "lives", "in", "London", "."}
+	entities, err := recognizer.Extract(tokens)
+	if err != nil {
+		t.Fatalf("Recognizer.Extract() error = %v", err)
+	}
+	want := []Entity{
+		{Tag: 2, TagString: "PERSON", Name: "Jones", Range: Range{1, 2}},
+		{Tag: 0, TagString: "LOCATION", Name: "London", Range: Range{4, 5}},
+	}
+	for i := range entities {
+		entities[i].Score = 0
+	}
+	if !reflect.DeepEqual(entities, want) {
+		t.Errorf("Recognizer.Extract() = %+v, want %+v", entities, want)
+	}
+
+	var saved bytes.Buffer
+	if err := recognizer.Save(&saved); err != nil {
+		t.Fatalf("Recognizer.Save() error = %v", err)
+	}
+	loaded, err := LoadRecognizer(bytes.NewReader(saved.Bytes()))
+	if err != nil {
+		t.Fatalf("LoadRecognizer() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Label(tokens), recognizer.Label(tokens)) {
+		t.Errorf("LoadRecognizer() labels %v, want %v", loaded.Label(tokens), recognizer.Label(tokens))
+	}
+	if _, err := LoadRecognizer(bytes.NewReader(saved.Bytes()[:saved.Len()/2])); err != ErrBadModel {
+		t.Errorf("LoadRecognizer() truncated error = %v, want %v", err, ErrBadModel)
+	}
+
+	bad := []tagger.TaggedWord{{Word: "Smith", Tag: "PERSON"}}
+	if _, err := Train(tagger.NewSliceSentenceReader([][]tagger.TaggedWord{bad})); err != ErrBadLabel {
+		t.Errorf("Train() error = %v, want %v", err, ErrBadLabel)
+	}
+}
+
+// The embedded model against hand labelled English text that is not in the
+// Brown Corpus, with names that are not in the gazetteer
+func TestEnglish(t *testing.T) {
+	file, err := os.Open("testdata/heldout.conll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	precision, recall := evaluateEnglish(t, NewCoNLLReader(file))
+	if f1 := 2 * precision * recall / (precision + recall); f1 < 0.6 {
+		t.Errorf("English() F1 = %.3f (precision %.3f, recall %.3f), want at least 0.6", f1, precision, recall)
+	}
+
+	// titles are context, never part of a name
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Mr.", nil},
+		{"Senator", nil},
+		{"Mr. Smith left.", []string{"Smith"}},
+	}
+	for _, tt := range tests {
+		entities, err := English().ExtractText(tt.text)
+		if err != nil {
+			t.Fatalf("Recognizer.ExtractText() error = %v", err)
+		}
+		var got []string
+		for _, entity := range entities {
+			got = append(got, entity.Text)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Recognizer.ExtractText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// The precision and recall of the entities English() finds in the gold
+// sentences
+func evaluateEnglish(t *testing.T, gold tagger.SentenceReader) (float64, float64) {
+	recognizer := English()
+	correct, predicted, total := 0, 0, 0
+	for _, sentence := range readAll(t, gold) {
+		tokens := make([]string, len(sentence))
+		want := make(map[Entity]bool)
+		for i := range sentence {
+			tokens[i] = sentence[i].Word
+		}
+		for i := range sentence {
+			if strings.HasPrefix(sentence[i].Tag, "B-") {
+				end := i + 1
+				for end < len(sentence) && sentence[end].Tag == "I-"+sentence[i].Tag[2:] {
+					end++
+				}
+				want[Entity{TagString: sentence[i].Tag[2:], Range: Range{i, end}}] = true
+				total++
+			}
+		}
+		entities, _ := recognizer.Extract(tokens)
+		for _, entity := range entities {
+			predicted++
+			if want[Entity{TagString: entity.TagString, Range: entity.Range}] {
+				correct++
+			}
+		}
+	}
+	return float64(correct) / float64(predicted), float64(correct) / float64(total)
+}
+
+func TestLocate(t *testing.T) {
+	text := "Café owner  Jean-Luc Picard's ship"
+	tokens := []string{"Café", "owner", "Jean-Luc", "Picard", "'s", "ship"}
+	entities := []Entity{{Name: "Jean-Luc Picard", Range: Range{2, 4}}, {Name: "Café", Range: Range{0, 1}}}
+	if err := Locate(text, tokens, entities); err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+	want := []Entity{
+		{Name: "Jean-Luc Picard", Range: Range{2, 4}, Text: "Jean-Luc Picard", ByteStart: 13, ByteEnd: 28, RuneStart: 12, RuneEnd: 27},
+		{Name: "Café", Range: Range{0, 1}, Text: "Café", ByteStart: 0, ByteEnd: 5, RuneStart: 0, RuneEnd: 4},
+	}
+	if !reflect.DeepEqual(entities, want) {
+		t.Errorf("Locate() = %+v, want %+v", entities, want)
+	}
+	if err := Locate(text, []string{"Picard", "Café"}, nil); err != ErrNotInText {
+		t.Errorf("Locate() error = %v, want %v", err, ErrNotInText)
+	}
+	if err := Locate(text, tokens, []Entity{{Range: Range{4, 7}}}); err != ErrBadRange {
+		t.Errorf("Locate() error = %v, want %v", err, ErrBadRange)
+	}
+}
+
+func TestRecognizer_ExtractText(t *testing.T) {
+	recognizer, err := Train(NewCoNLLReader(strings.NewReader(testCorpus)), WithGazetteer(NewGazetteer()), WithIterations(10))
+	if err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+	text := "Mr. Jones  lives in Zürich's London."
+	tokens := TokenizeWordsWithOffsets(text)
+	for _, token := range tokens {
+		if text[token.ByteStart:token.ByteEnd] != token.Text || string([]rune(text)[token.RuneStart:token.RuneEnd]) != token.Text {
+			t.Errorf("TokenizeWordsWithOffsets() offsets of %q do not match the text", token.Text)
+		}
+	}
+	entities, err := recognizer.ExtractText(text)
+	if err != nil {
+		t.Fatalf("Recognizer.ExtractText() error = %v", err)
+	}
+	found := make(map[string]string)
+	for _, entity := range entities {
+		if text[entity.ByteStart:entity.ByteEnd] != entity.Text || string([]rune(text)[entity.RuneStart:entity.RuneEnd]) != entity.Text {
+			t.Errorf("Recognizer.ExtractText() offsets of %q do not match the text", entity.Text)
+		}
+		found[entity.Text] = entity.TagString
+	}
+	if found["Jones"] != "PERSON" || found["London"] != "LOCATION" {
+		t.Errorf("Recognizer.ExtractText() = %v", found)
+	}
+}
+
+// Needs a MITIE model and a relation model trained with it, e.g.
+// MITIE-models/english/binary_relations/rel_classifier_people.person.place_of_birth.svm
+func TestRelationDetector_Detect(t *testing.T) {
+	model, relationModel := os.Getenv("MITIE_MODEL"), os.Getenv("MITIE_RELATION_MODEL")
+	if model == "" || relationModel == "" {
+		t.Skip("MITIE_MODEL or MITIE_RELATION_MODEL is not set")
+	}
+	ext, err := NewExtractor(model)
+	if err != nil {
+		t.Fatalf("NewExtractor() error = %v", err)
+	}
+	defer ext.Free()
+	detector, err := NewRelationDetector(relationModel)
+	if err != nil {
+		t.Fatalf("NewRelationDetector() error = %v", err)
+	}
+	defer detector.Free()
+
+	tokens := Tokenize("Barack Obama was born in Honolulu .")
+	entities, err := ext.Extract(tokens)
+	if err != nil {
+		t.Fatalf("Extractor.Extract() error = %v", err)
+	}
+	relations, err := detector.Detect(ext, tokens, entities)
+	if err != nil {
+		t.Fatalf("RelationDetector.Detect() error = %v", err)
+	}
+	for i, relation := range relations {
+		if relation.Name != detector.Name() || relation.Score <= 0 || (i > 0 && relation.Score > relations[i-1].Score) {
+			t.Errorf("RelationDetector.Detect() = %+v", relation)
+		}
+	}
+	if len(entities) > 0 {
+		if _, err := detector.Score(ext, tokens, entities[0], entities[0]); err != ErrOverlap {
+			t.Errorf("RelationDetector.Score() error = %v, want %v", err, ErrOverlap)
+		}
+	}
+}
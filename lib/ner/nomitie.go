@@ -0,0 +1,72 @@
+//go:build !mitie
+
+package ner
+
+import "errors"
+
+// ErrNoMITIE is returned by NewExtractor in builds without MITIE, that is
+// without the mitie build tag. Recognizer works in any build.
+var ErrNoMITIE = errors.New("Built without MITIE")
+
+// Extractor would use a MITIE model, this build has none
+type Extractor struct{}
+
+// NewExtractor always fails in builds without MITIE
+func NewExtractor(path string) (*Extractor, error) {
+	return nil, ErrNoMITIE
+}
+
+// Tokenize splits text like TokenizeWords as mitie_tokenize is not there
+func Tokenize(text string) []string {
+	return TokenizeWords(text)
+}
+
+// TokenizeWithOffsets splits text like TokenizeWordsWithOffsets as
+// mitie_tokenize_with_offsets is not there
+func TokenizeWithOffsets(text string) []Token {
+	return TokenizeWordsWithOffsets(text)
+}
+
+// Free does nothing
+func (ext *Extractor) Free() {}
+
+// Tags returns no tags
+func (ext *Extractor) Tags() []string {
+	return nil
+}
+
+// Extract always fails in builds without MITIE
+func (ext *Extractor) Extract(tokens []string) ([]Entity, error) {
+	return nil, ErrNoMITIE
+}
+
+// ExtractText always fails in builds without MITIE
+func (ext *Extractor) ExtractText(text string) ([]Entity, error) {
+	return nil, ErrNoMITIE
+}
+
+// RelationDetector would use a MITIE relation model, this build has none
+type RelationDetector struct{}
+
+// NewRelationDetector always fails in builds without MITIE
+func NewRelationDetector(path string) (*RelationDetector, error) {
+	return nil, ErrNoMITIE
+}
+
+// Free does nothing
+func (d *RelationDetector) Free() {}
+
+// Name returns no name
+func (d *RelationDetector) Name() string {
+	return ""
+}
+
+// Score always fails in builds without MITIE
+func (d *RelationDetector) Score(ext *Extractor, tokens []string, arg1 Entity, arg2 Entity) (float64, error) {
+	return 0, ErrNoMITIE
+}
+
+// Detect always fails in builds without MITIE
+func (d *RelationDetector) Detect(ext *Extractor, tokens []string, entities []Entity) ([]Relation, error) {
+	return nil, ErrNoMITIE
+}
@@ -0,0 +1,315 @@
+package ner
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// The label of the tokens outside of every entity. The others are B-TYPE on
+// the first token of an entity and I-TYPE on the rest.
+const outside = "O"
+
+// The padding around a sentence, so the features of the first and last
+// tokens can look past the edges
+const (
+	startToken = "-START-"
+	endToken   = "-END-"
+)
+
+// Recognizer is a named entity recognizer in pure Go, an averaged perceptron
+// that labels the tokens one after the other. The part of speech tags of the
+// HMM of lib/tagger and the names of a Gazetteer are among its features. It
+// is not changed after training or loading, so one Recognizer can extract
+// from many goroutines at once.
+type Recognizer struct {
+	// O, B-TYPE and I-TYPE labels
+	labels []string
+	// the entity types, sorted, and the type of every label, -1 for O
+	types     []string
+	labelType []int
+	// the index of every feature into weights
+	features  map[string]int
+	weights   [][]classWeight
+	gazetteer *Gazetteer
+}
+
+// The weight of a feature for one label
+type classWeight struct {
+	Class  int
+	Weight float64
+}
+
+// The part of speech tagger whose tags are features, the HMM of lib/tagger
+// pre-trained on the Brown Corpus
+func partOfSpeech() *tagger.Tagger {
+	return tagger.Brown()
+}
+
+// Tags returns the entity types the Recognizer finds, the Tag of an Entity
+// is an index into them
+func (r *Recognizer) Tags() []string {
+	return append([]string(nil), r.types...)
+}
+
+// Gazetteer returns the gazetteer the Recognizer was trained with
+func (r *Recognizer) Gazetteer() *Gazetteer {
+	return r.gazetteer
+}
+
+// Free does nothing, a Recognizer is in Go memory. It is there so
+// Recognizer and Extractor can be used the same way.
+func (r *Recognizer) Free() {}
+
+// Extract returns the entities found in the tokens
+func (r *Recognizer) Extract(tokens []string) ([]Entity, error) {
+	labels, margins := r.label(tokens)
+	var entities []Entity
+	for start := 0; start < len(labels); {
+		typeIndex := r.labelType[labels[start]]
+		if typeIndex < 0 {
+			start++
+			continue
+		}
+		end := start + 1
+		for end < len(labels) && r.labels[labels[end]] == "I-"+r.types[typeIndex] {
+			end++
+		}
+		// a title is context of a name, never part of it
+		first, last := start, end
+		for first < last && r.isTitle(tokens[first]) {
+			first++
+		}
+		for last > first && r.isTitle(tokens[last-1]) {
+			last--
+		}
+		if first < last {
+			score := 0.0
+			for i := first; i < last; i++ {
+				score += margins[i]
+			}
+			entities = append(entities, Entity{
+				Score:     score / float64(last-first),
+				Tag:       typeIndex,
+				TagString: r.types[typeIndex],
+				Name:      strings.Join(tokens[first:last], " "),
+				Range:     Range{first, last},
+			})
+		}
+		start = end
+	}
+	return entities, nil
+}
+
+// Whether the token is in the title list of the gazetteer, Mr is as Mr.
+func (r *Recognizer) isTitle(token string) bool {
+	if r.gazetteer == nil {
+		return false
+	}
+	return r.gazetteer.Contains(TitleList, token) || r.gazetteer.Contains(TitleList, token+".")
+}
+
+// ExtractText returns the entities of the text, split into tokens by
+// TokenizeWordsWithOffsets like the training data of the English model
+func (r *Recognizer) ExtractText(text string) ([]Entity, error) {
+	return extractTokens(r, text, TokenizeWordsWithOffsets(text))
+}
+
+// Label returns the BIO label of every token, like B-PERSON, I-PERSON or O
+func (r *Recognizer) Label(tokens []string) []string {
+	indexes, _ := r.label(tokens)
+	labels := make([]string, len(indexes))
+	for i, index := range indexes {
+		labels[i] = r.labels[index]
+	}
+	return labels
+}
+
+// Labels the tokens left to right, each label is a feature of the next. The
+// margins are how far every label is ahead of the next best one.
+func (r *Recognizer) label(tokens []string) ([]int, []float64) {
+	labels := make([]int, len(tokens))
+	margins := make([]float64, len(tokens))
+	if len(r.labels) == 0 {
+		return labels, margins
+	}
+	context := newSentenceContext(tokens, r.gazetteer)
+	scores := make([]float64, len(r.labels))
+	prev, prev2 := startToken, startToken
+	for i := range tokens {
+		r.score(context.features(i, prev, prev2), scores)
+		labels[i], margins[i] = r.best(scores, prev)
+		prev2, prev = prev, r.labels[labels[i]]
+	}
+	return labels, margins
+}
+
+// Adds up the weights of the features for every label
+func (r *Recognizer) score(features []string, scores []float64) {
+	for i := range scores {
+		scores[i] = 0
+	}
+	for _, feature := range features {
+		index, ok := r.features[feature]
+		if !ok {
+			continue
+		}
+		for _, weight := range r.weights[index] {
+			scores[weight.Class] += weight.Weight
+		}
+	}
+}
+
+// The label with the highest score that can follow prev, an I- label only
+// continues an entity of its type. Ties go to the label that sorts last so
+// the result does not depend on the order labels were seen in.
+func (r *Recognizer) best(scores []float64, prev string) (int, float64) {
+	best, second := -1, -1
+	for i, label := range r.labels {
+		if strings.HasPrefix(label, "I-") && prev != "B-"+label[2:] && prev != label {
+			continue
+		}
+		switch {
+		case best < 0 || scores[i] > scores[best] || scores[i] == scores[best] && label > r.labels[best]:
+			best, second = i, best
+		case second < 0 || scores[i] > scores[second]:
+			second = i
+		}
+	}
+	if second < 0 {
+		return best, 0
+	}
+	return best, scores[best] - scores[second]
+}
+
+// What the features of a sentence need that does not depend on the labels
+type sentenceContext struct {
+	lower  []string
+	shapes []string
+	pos    []string
+	gaz    [][]string
+}
+
+// Pads the sentence with a start and an end token on both sides
+func newSentenceContext(tokens []string, gazetteer *Gazetteer) *sentenceContext {
+	n := len(tokens) + 4
+	context := &sentenceContext{
+		lower:  make([]string, 0, n),
+		shapes: make([]string, 0, n),
+		pos:    make([]string, 0, n),
+		gaz:    make([][]string, 2, n),
+	}
+	context.lower = append(context.lower, startToken, startToken)
+	context.shapes = append(context.shapes, startToken, startToken)
+	context.pos = append(context.pos, startToken, startToken)
+	for _, token := range tokens {
+		context.lower = append(context.lower, strings.ToLower(token))
+		context.shapes = append(context.shapes, shape(token))
+	}
+	context.pos = append(context.pos, partOfSpeech().TagWords(tokens)...)
+	if gazetteer != nil {
+		context.gaz = append(context.gaz, gazetteer.Match(tokens)...)
+	} else {
+		context.gaz = append(context.gaz, make([][]string, len(tokens))...)
+	}
+	context.lower = append(context.lower, endToken, endToken)
+	context.shapes = append(context.shapes, endToken, endToken)
+	context.pos = append(context.pos, endToken, endToken)
+	context.gaz = append(context.gaz, nil, nil)
+	return context
+}
+
+// The same context with no names found in the gazetteer
+func (c *sentenceContext) withoutGazetteer() *sentenceContext {
+	without := *c
+	without.gaz = make([][]string, len(c.gaz))
+	return &without
+}
+
+// The features of the token at i given the labels of the two tokens before
+func (c *sentenceContext) features(i int, prev string, prev2 string) []string {
+	i += 2
+	word := c.lower[i]
+	features := []string{
+		"bias",
+		"w " + word,
+		"shape " + c.shapes[i],
+		"suffix " + suffix(word),
+		"prefix " + prefix(word),
+		"pos " + c.pos[i],
+		"pos-1 " + c.pos[i-1],
+		"pos+1 " + c.pos[i+1],
+		"pos-1 pos " + c.pos[i-1] + " " + c.pos[i],
+		"pos pos+1 " + c.pos[i] + " " + c.pos[i+1],
+		"w-1 " + c.lower[i-1],
+		"w-2 " + c.lower[i-2],
+		"w+1 " + c.lower[i+1],
+		"w+2 " + c.lower[i+2],
+		"shape-1 " + c.shapes[i-1],
+		"shape+1 " + c.shapes[i+1],
+		"label-1 " + prev,
+		"label-2 label-1 " + prev2 + " " + prev,
+		"label-1 shape " + prev + " " + c.shapes[i],
+		"label-1 w " + prev + " " + word,
+	}
+	if i == 2 {
+		// a capital letter says little at the start of a sentence
+		features = append(features, "first shape "+c.shapes[i])
+	}
+	for _, match := range c.gaz[i] {
+		features = append(features, "gaz "+match)
+	}
+	for _, match := range c.gaz[i-1] {
+		features = append(features, "gaz-1 "+match)
+	}
+	for _, match := range c.gaz[i+1] {
+		features = append(features, "gaz+1 "+match)
+	}
+	return features
+}
+
+// The last three letters of the word
+func suffix(word string) string {
+	runes := []rune(word)
+	if len(runes) > 3 {
+		return string(runes[len(runes)-3:])
+	}
+	return word
+}
+
+// The first three letters of the word
+func prefix(word string) string {
+	for i := range word {
+		if utf8.RuneCountInString(word[:i]) == 3 {
+			return word[:i]
+		}
+	}
+	return word
+}
+
+// The shape of the word with runs of the same kind of letter collapsed, so
+// Smith is Xx, IBM is X, 1960s is dx and U.S. is X.X.
+func shape(word string) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range word {
+		var kind rune
+		switch {
+		case unicode.IsUpper(r):
+			kind = 'X'
+		case unicode.IsLetter(r):
+			kind = 'x'
+		case unicode.IsDigit(r):
+			kind = 'd'
+		default:
+			kind = r
+		}
+		if kind != last {
+			b.WriteRune(kind)
+			last = kind
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+//go:build mitie
+
+package ner
+
+/*
+#include <stdlib.h>
+#include "mitie.h"
+*/
+import "C"
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// RelationDetector finds one kind of binary relation between entities, like
+// people.person.place_of_birth, with a MITIE .svm relation model. A
+// detector only works with the Extractor of the NER model it was trained
+// with.
+type RelationDetector struct {
+	detector *C.mitie_binary_relation_detector
+}
+
+// NewRelationDetector returns a RelationDetector given the path to a MITIE
+// relation model.
+func NewRelationDetector(path string) (*RelationDetector, error) {
+	model := C.CString(path)
+	defer C.free(unsafe.Pointer(model))
+	detector := C.mitie_load_binary_relation_detector(model)
+	if detector == nil {
+		return nil, ErrCantOpen
+	}
+	return &RelationDetector{detector: detector}, nil
+}
+
+// Free frees the underlying used C memory.
+func (d *RelationDetector) Free() {
+	C.mitie_free(unsafe.Pointer(d.detector))
+}
+
+// Name returns the relation the detector finds, e.g.
+// people.person.place_of_birth.
+func (d *RelationDetector) Name() string {
+	return C.GoString(C.mitie_binary_relation_detector_name_string(d.detector))
+}
+
+// Score returns how strongly the relation holds from arg1 to arg2, both
+// entities found by ext in tokens. The relation holds when the score is
+// above 0.
+func (d *RelationDetector) Score(ext *Extractor, tokens []string, arg1 Entity, arg2 Entity) (float64, error) {
+	ctokens := makeCTokens(tokens)
+	defer freeCTokens(ctokens, len(tokens))
+	return d.score(ext, ctokens, len(tokens), arg1, arg2)
+}
+
+func (d *RelationDetector) score(ext *Extractor, ctokens **C.char, numTokens int, arg1 Entity, arg2 Entity) (float64, error) {
+	for _, r := range []Range{arg1.Range, arg2.Range} {
+		if r.Start < 0 || r.Start >= r.End || r.End > numTokens {
+			return 0, ErrBadRange
+		}
+	}
+	start1, length1 := C.ulong(arg1.Range.Start), C.ulong(arg1.Range.End-arg1.Range.Start)
+	start2, length2 := C.ulong(arg2.Range.Start), C.ulong(arg2.Range.End-arg2.Range.Start)
+	if C.mitie_entities_overlap(start1, length1, start2, length2) != 0 {
+		return 0, ErrOverlap
+	}
+	relation := C.mitie_extract_binary_relation(ext.ner, ctokens, start1, length1, start2, length2)
+	if relation == nil {
+		return 0, ErrMemory
+	}
+	defer C.mitie_free(unsafe.Pointer(relation))
+	var score C.double
+	if C.mitie_classify_binary_relation(d.detector, relation, &score) != 0 {
+		return 0, ErrIncompatibleModels
+	}
+	return float64(score), nil
+}
+
+// Detect scores every ordered pair of entities found by ext in tokens and
+// returns the pairs the relation holds for, the highest score first.
+func (d *RelationDetector) Detect(ext *Extractor, tokens []string, entities []Entity) ([]Relation, error) {
+	ctokens := makeCTokens(tokens)
+	defer freeCTokens(ctokens, len(tokens))
+
+	name := d.Name()
+	var relations []Relation
+	for i := range entities {
+		for j := range entities {
+			if i == j || overlap(entities[i].Range, entities[j].Range) {
+				continue
+			}
+			score, err := d.score(ext, ctokens, len(tokens), entities[i], entities[j])
+			if err != nil {
+				return nil, err
+			}
+			if score > 0 {
+				relations = append(relations, Relation{Name: name, Score: score, Arg1: entities[i], Arg2: entities[j]})
+			}
+		}
+	}
+	sort.SliceStable(relations, func(i, j int) bool {
+		return relations[i].Score > relations[j].Score
+	})
+	return relations, nil
+}
@@ -0,0 +1,71 @@
+package ner
+
+import (
+	"unicode/utf8"
+
+	"github.com/modquiz/go-nltb/lib/tokenize"
+)
+
+// Token is a token with its location in the text it was split from, the ends
+// are exclusive so text[ByteStart:ByteEnd] is the token
+type Token struct {
+	Text      string
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+}
+
+// TokenizeWords returns a slice that contains a tokenized copy of the input
+// text, split by the rule based tokenizer the part of speech tagger uses
+// instead of mitie_tokenize. Clitics are split off following the Penn
+// Treebank, which is how the CoNLL data the English models come from is
+// tokenized.
+func TokenizeWords(text string) []string {
+	return words(TokenizeWordsWithOffsets(text))
+}
+
+// TokenizeWordsWithOffsets is TokenizeWords with the location of every token
+func TokenizeWordsWithOffsets(text string) []Token {
+	tokens := tokenize.Treebank.Tokenize(text)
+	located := make([]Token, len(tokens))
+	for i, token := range tokens {
+		located[i] = Token{Text: token.Text, ByteStart: token.ByteStart, ByteEnd: token.ByteEnd, RuneStart: token.RuneStart, RuneEnd: token.RuneEnd}
+	}
+	return located
+}
+
+// The text of every token
+func words(tokens []Token) []string {
+	words := make([]string, len(tokens))
+	for i := range tokens {
+		words[i] = tokens[i].Text
+	}
+	return words
+}
+
+// Fills in the rune offsets of tokens whose byte offsets are known, the
+// tokens are in the order of the text
+func setRuneOffsets(text string, tokens []Token) {
+	position, runes := 0, 0
+	for i := range tokens {
+		runes += utf8.RuneCountInString(text[position:tokens[i].ByteStart])
+		tokens[i].RuneStart = runes
+		runes += utf8.RuneCountInString(text[tokens[i].ByteStart:tokens[i].ByteEnd])
+		tokens[i].RuneEnd = runes
+		position = tokens[i].ByteEnd
+	}
+}
+
+// Extracts the entities of the tokens and locates them in text by the
+// offsets of the tokens
+func extractTokens(extractor EntityExtractor, text string, tokens []Token) ([]Entity, error) {
+	entities, err := extractor.Extract(words(tokens))
+	if err != nil {
+		return nil, err
+	}
+	if err := locateTokens(text, tokens, entities); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
@@ -0,0 +1,269 @@
+package ner
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// The share of training sentences seen without their gazetteer features, so
+// the model also learns the names the gazetteer does not know from their
+// context
+const gazetteerDropout = 0.5
+
+// ErrBadLabel is returned by Train for labels that are not O, B-TYPE or I-TYPE
+var ErrBadLabel = errors.New("Not a BIO label")
+
+// TrainOption configures the training of a Recognizer
+type TrainOption func(*trainOptions)
+
+type trainOptions struct {
+	iterations int
+	seed       int64
+	gazetteer  *Gazetteer
+}
+
+// WithIterations sets how many times training goes over the corpus, 5 by
+// default
+func WithIterations(iterations int) TrainOption {
+	return func(o *trainOptions) {
+		o.iterations = iterations
+	}
+}
+
+// WithSeed sets the seed of the shuffle before every iteration, training
+// with the same seed and corpus gives the same Recognizer
+func WithSeed(seed int64) TrainOption {
+	return func(o *trainOptions) {
+		o.seed = seed
+	}
+}
+
+// WithGazetteer sets the names that are features, EnglishGazetteer by
+// default. The gazetteer is saved with the model.
+func WithGazetteer(gazetteer *Gazetteer) TrainOption {
+	return func(o *trainOptions) {
+		o.gazetteer = gazetteer
+	}
+}
+
+// Train learns a Recognizer from sentences whose tags are BIO labels, like
+// the ones NewCoNLLReader reads. Every sentence is held in memory as it is
+// read again on every iteration.
+func Train(sentences tagger.SentenceReader, opts ...TrainOption) (*Recognizer, error) {
+	o := trainOptions{iterations: 5, seed: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.gazetteer == nil {
+		o.gazetteer = EnglishGazetteer()
+	}
+
+	var corpus []trainingSentence
+	labelSet := map[string]bool{outside: true}
+	for {
+		sentence, err := sentences.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(sentence) == 0 {
+			continue
+		}
+		s := trainingSentence{tokens: make([]string, len(sentence)), labels: make([]string, len(sentence))}
+		for i := range sentence {
+			label := sentence[i].Tag
+			if label != outside && !strings.HasPrefix(label, "B-") && !strings.HasPrefix(label, "I-") || len(label) == 2 {
+				return nil, ErrBadLabel
+			}
+			s.tokens[i], s.labels[i] = sentence[i].Word, label
+			labelSet[label] = true
+			if label != outside {
+				// every type can start an entity and go on
+				labelSet["B-"+label[2:]], labelSet["I-"+label[2:]] = true, true
+			}
+		}
+		corpus = append(corpus, s)
+	}
+	if len(corpus) == 0 {
+		return nil, tagger.ErrNoTrainingData
+	}
+
+	train := newNERTrainer(labelSet, o.gazetteer)
+	for i := range corpus {
+		corpus[i].context = newSentenceContext(corpus[i].tokens, o.gazetteer)
+	}
+	random := rand.New(rand.NewSource(o.seed))
+	for iteration := 0; iteration < o.iterations; iteration++ {
+		for _, sentence := range corpus {
+			context := sentence.context
+			if random.Float64() < gazetteerDropout {
+				context = context.withoutGazetteer()
+			}
+			train.trainSentence(sentence, context)
+		}
+		random.Shuffle(len(corpus), func(i, j int) {
+			corpus[i], corpus[j] = corpus[j], corpus[i]
+		})
+	}
+	return train.finish(), nil
+}
+
+type trainingSentence struct {
+	tokens  []string
+	labels  []string
+	context *sentenceContext
+}
+
+// The running weights of a feature for one label. The total sums the weight
+// over every update so far, and is brought up to date from stamp lazily.
+type runningWeight struct {
+	class  int
+	weight float64
+	total  float64
+	stamp  int
+}
+
+type nerTrainer struct {
+	recognizer *Recognizer
+	labelIdx   map[string]int
+	weights    [][]runningWeight
+	// the number of tokens trained on so far
+	instances int
+	scores    []float64
+}
+
+func newNERTrainer(labelSet map[string]bool, gazetteer *Gazetteer) *nerTrainer {
+	labels := make([]string, 0, len(labelSet))
+	for label := range labelSet {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	train := &nerTrainer{
+		recognizer: newRecognizer(labels, gazetteer),
+		labelIdx:   make(map[string]int),
+		scores:     make([]float64, len(labels)),
+	}
+	for i, label := range labels {
+		train.labelIdx[label] = i
+	}
+	return train
+}
+
+// Labels the sentence with the current weights and corrects them where the
+// guess is wrong. The features see the guessed labels, as they will when
+// the Recognizer is used.
+func (train *nerTrainer) trainSentence(sentence trainingSentence, context *sentenceContext) {
+	r := train.recognizer
+	prev, prev2 := startToken, startToken
+	for i := range sentence.tokens {
+		features := context.features(i, prev, prev2)
+		train.score(features)
+		guess, _ := r.best(train.scores, prev)
+		train.update(train.labelIdx[sentence.labels[i]], guess, features)
+		prev2, prev = prev, r.labels[guess]
+	}
+}
+
+func (train *nerTrainer) score(features []string) {
+	scores := train.scores
+	for i := range scores {
+		scores[i] = 0
+	}
+	for _, feature := range features {
+		if index, ok := train.recognizer.features[feature]; ok {
+			for _, w := range train.weights[index] {
+				scores[w.class] += w.weight
+			}
+		}
+	}
+}
+
+func (train *nerTrainer) update(truth int, guess int, features []string) {
+	train.instances++
+	if truth == guess {
+		return
+	}
+	for _, feature := range features {
+		index, ok := train.recognizer.features[feature]
+		if !ok {
+			index = len(train.weights)
+			train.recognizer.features[feature] = index
+			train.weights = append(train.weights, nil)
+		}
+		train.updateWeight(index, truth, 1)
+		train.updateWeight(index, guess, -1)
+	}
+}
+
+func (train *nerTrainer) updateWeight(index int, class int, delta float64) {
+	weights := train.weights[index]
+	for i := range weights {
+		if weights[i].class == class {
+			w := &weights[i]
+			w.total += float64(train.instances-w.stamp) * w.weight
+			w.stamp = train.instances
+			w.weight += delta
+			return
+		}
+	}
+	train.weights[index] = append(weights, runningWeight{class: class, weight: delta, stamp: train.instances})
+}
+
+// Averages every weight over all the updates and drops the features left
+// without weights
+func (train *nerTrainer) finish() *Recognizer {
+	r := train.recognizer
+	features := r.features
+	r.features = make(map[string]int, len(features))
+	for feature, index := range features {
+		var weights []classWeight
+		for _, w := range train.weights[index] {
+			total := w.total + float64(train.instances-w.stamp)*w.weight
+			if average := total / float64(train.instances); average != 0 {
+				weights = append(weights, classWeight{Class: w.class, Weight: average})
+			}
+		}
+		if len(weights) > 0 {
+			r.features[feature] = len(r.weights)
+			r.weights = append(r.weights, weights)
+		}
+	}
+	return r
+}
+
+// A Recognizer for the labels, with the entity types taken from them
+func newRecognizer(labels []string, gazetteer *Gazetteer) *Recognizer {
+	r := &Recognizer{
+		labels:    labels,
+		labelType: make([]int, len(labels)),
+		features:  make(map[string]int),
+		gazetteer: gazetteer,
+	}
+	typeIndex := make(map[string]int)
+	for _, label := range labels {
+		if label != outside {
+			if _, ok := typeIndex[label[2:]]; !ok {
+				typeIndex[label[2:]] = len(r.types)
+				r.types = append(r.types, label[2:])
+			}
+		}
+	}
+	sort.Strings(r.types)
+	for i, entityType := range r.types {
+		typeIndex[entityType] = i
+	}
+	for i, label := range labels {
+		r.labelType[i] = -1
+		if label != outside {
+			r.labelType[i] = typeIndex[label[2:]]
+		}
+	}
+	return r
+}
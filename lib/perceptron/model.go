@@ -0,0 +1,130 @@
+package perceptron
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+	"sort"
+)
+
+// ModelVersion is the version of the model format written by Save. Load
+// refuses models written with any other version.
+const ModelVersion = 1
+
+var (
+	// ErrBadModel is returned by Load when the data is not a perceptron model
+	ErrBadModel = errors.New("Not a perceptron model")
+	// ErrModelVersion is returned by Load for models of an unsupported version
+	ErrModelVersion = errors.New("Unsupported perceptron model version")
+)
+
+// The gob encoded form of a Tagger, features and words are sorted so the
+// same model always produces the same bytes
+type savedModel struct {
+	Magic    string
+	Version  int
+	Tags     []string
+	Features []string
+	Weights  [][]classWeight
+	TagDict  map[string]int
+	Known    []string
+}
+
+const modelMagic = "NLTBPERCEPTRON"
+
+// Save writes the trained model to w for Load to read back
+func (t *Tagger) Save(w io.Writer) error {
+	model := savedModel{
+		Magic:    modelMagic,
+		Version:  ModelVersion,
+		Tags:     t.tags,
+		Features: make([]string, 0, len(t.features)),
+		TagDict:  t.tagDict,
+		Known:    make([]string, 0, len(t.known)),
+	}
+	for feature := range t.features {
+		model.Features = append(model.Features, feature)
+	}
+	sort.Strings(model.Features)
+	model.Weights = make([][]classWeight, len(model.Features))
+	for i, feature := range model.Features {
+		model.Weights[i] = t.weights[t.features[feature]]
+	}
+	for word := range t.known {
+		model.Known = append(model.Known, word)
+	}
+	sort.Strings(model.Known)
+
+	bw := bufio.NewWriter(w)
+	if err := gob.NewEncoder(bw).Encode(&model); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// SaveFile writes the model to the file at path, see Save
+func (t *Tagger) SaveFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := t.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads a model written by Save
+func Load(r io.Reader) (*Tagger, error) {
+	var model savedModel
+	if err := gob.NewDecoder(bufio.NewReader(r)).Decode(&model); err != nil || model.Magic != modelMagic {
+		return nil, ErrBadModel
+	}
+	if model.Version != ModelVersion {
+		return nil, ErrModelVersion
+	}
+	if len(model.Weights) != len(model.Features) {
+		return nil, ErrBadModel
+	}
+
+	t := &Tagger{
+		tags:     model.Tags,
+		features: make(map[string]int, len(model.Features)),
+		weights:  model.Weights,
+		tagDict:  model.TagDict,
+		known:    make(map[string]bool, len(model.Known)),
+	}
+	if t.tagDict == nil {
+		t.tagDict = make(map[string]int)
+	}
+	for i, feature := range model.Features {
+		t.features[feature] = i
+		for _, weight := range model.Weights[i] {
+			if weight.Class < 0 || weight.Class >= len(t.tags) {
+				return nil, ErrBadModel
+			}
+		}
+	}
+	for _, tagIndex := range t.tagDict {
+		if tagIndex < 0 || tagIndex >= len(t.tags) {
+			return nil, ErrBadModel
+		}
+	}
+	for _, word := range model.Known {
+		t.known[word] = true
+	}
+	return t, nil
+}
+
+// LoadFile reads the model in the file at path, see Load
+func LoadFile(path string) (*Tagger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
@@ -0,0 +1,188 @@
+// Package perceptron is a part of speech tagger using an averaged perceptron,
+// the model behind NLTK's default pos_tag (Honnibal 2013). It is slower to
+// train than the HMM of lib/tagger but more accurate, and it is trained from
+// the same tagger.SentenceReader sources.
+package perceptron
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// The padding around a sentence, so the features of the first and last
+// words can look past the edges
+var (
+	start = [2]string{"-START-", "-START2-"}
+	end   = [2]string{"-END-", "-END2-"}
+)
+
+// Tagger is a trained averaged perceptron. It is not changed after training
+// or loading, so one Tagger can tag from many goroutines at once.
+type Tagger struct {
+	tags []string
+	// the index of every feature into weights
+	features map[string]int
+	weights  [][]classWeight
+	// words frequent and unambiguous enough to be tagged without the model
+	tagDict map[string]int
+	// every word seen in training
+	known map[string]bool
+}
+
+// The weight of a feature for one tag
+type classWeight struct {
+	Class  int
+	Weight float64
+}
+
+var _ tagger.SequenceTagger = (*Tagger)(nil)
+
+// Tags returns every tag the Tagger can give, in index order
+func (t *Tagger) Tags() []string {
+	return append([]string(nil), t.tags...)
+}
+
+// Known reports whether the word was seen in training
+func (t *Tagger) Known(word string) bool {
+	return t.known[word]
+}
+
+// TagWords returns the most likely tag for every word of a sentence that is
+// already split into words. Tags are picked left to right, each one is a
+// feature of the next.
+func (t *Tagger) TagWords(words []string) []string {
+	tags := make([]string, len(words))
+	if len(t.tags) == 0 {
+		return tags
+	}
+	context := makeContext(words)
+	scores := make([]float64, len(t.tags))
+	prev, prev2 := start[0], start[1]
+	for i, word := range words {
+		tagIndex, ok := t.tagDict[word]
+		if !ok {
+			tagIndex = t.predict(getFeatures(i, word, context, prev, prev2), scores)
+		}
+		tags[i] = t.tags[tagIndex]
+		prev2, prev = prev, tags[i]
+	}
+	return tags
+}
+
+// Returns the tag with the highest score, scores is scratch space
+func (t *Tagger) predict(features []string, scores []float64) int {
+	for i := range scores {
+		scores[i] = 0
+	}
+	for _, feature := range features {
+		index, ok := t.features[feature]
+		if !ok {
+			continue
+		}
+		for _, weight := range t.weights[index] {
+			scores[weight.Class] += weight.Weight
+		}
+	}
+	return bestClass(scores, t.tags)
+}
+
+// The index of the highest score, ties go to the tag that sorts last as in
+// NLTK so the result does not depend on the order tags were seen in
+func bestClass(scores []float64, tags []string) int {
+	best := 0
+	for i := 1; i < len(scores); i++ {
+		if scores[i] > scores[best] || scores[i] == scores[best] && tags[i] > tags[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// The sentence as the features see it, normalized and padded
+func makeContext(words []string) []string {
+	context := make([]string, 0, len(words)+4)
+	context = append(context, start[:]...)
+	for _, word := range words {
+		context = append(context, normalize(word))
+	}
+	return append(context, end[:]...)
+}
+
+// Collapses the words that are too many to learn one by one
+func normalize(word string) string {
+	first, _ := utf8.DecodeRuneInString(word)
+	switch {
+	case strings.Contains(word, "-") && first != '-':
+		return "!HYPHEN"
+	case len(word) == 4 && strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) < 0:
+		return "!YEAR"
+	case unicode.IsDigit(first):
+		return "!DIGITS"
+	}
+	return strings.ToLower(word)
+}
+
+// The features of the word at i, NLTK's set plus the shape of the word
+func getFeatures(i int, word string, context []string, prev string, prev2 string) []string {
+	i += len(start)
+	return []string{
+		"bias",
+		"i suffix " + suffix(word),
+		"i pref1 " + prefix(word),
+		"i shape " + shape(word),
+		"i-1 tag " + prev,
+		"i-2 tag " + prev2,
+		"i tag+i-2 tag " + prev + " " + prev2,
+		"i word " + context[i],
+		"i-1 tag+i word " + prev + " " + context[i],
+		"i-1 word " + context[i-1],
+		"i-1 suffix " + suffix(context[i-1]),
+		"i-2 word " + context[i-2],
+		"i+1 word " + context[i+1],
+		"i+1 suffix " + suffix(context[i+1]),
+		"i+2 word " + context[i+2],
+	}
+}
+
+// The last three letters of the word
+func suffix(word string) string {
+	runes := []rune(word)
+	if len(runes) > 3 {
+		return string(runes[len(runes)-3:])
+	}
+	return word
+}
+
+// The first letter of the word
+func prefix(word string) string {
+	_, size := utf8.DecodeRuneInString(word)
+	return word[:size]
+}
+
+// The shape of the word with runs of the same kind of letter collapsed, so
+// Smith is Xx, 1960s is dx and e-mail is x-x
+func shape(word string) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range word {
+		var kind rune
+		switch {
+		case unicode.IsUpper(r):
+			kind = 'X'
+		case unicode.IsLetter(r):
+			kind = 'x'
+		case unicode.IsDigit(r):
+			kind = 'd'
+		default:
+			kind = r
+		}
+		if kind != last {
+			b.WriteRune(kind)
+			last = kind
+		}
+	}
+	return b.String()
+}
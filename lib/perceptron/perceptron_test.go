@@ -0,0 +1,79 @@
+package perceptron
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+const testCorpus = "The/at dog/nn walks/vbz ./.\n" +
+	"A/at cat/nn runs/vbz home/nr ./.\n" +
+	"The/at cats/nns walk/vb ./.\n" +
+	"Dogs/nns run/vb in/in 1960/cd ./.\n"
+
+func trainTest(t *testing.T) *Tagger {
+	posTagger, err := Train(tagger.NewSentenceReader(strings.NewReader(strings.Repeat(testCorpus, 5))), WithIterations(5))
+	if err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+	return posTagger
+}
+
+func TestTagger_TagWords(t *testing.T) {
+	posTagger := trainTest(t)
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"The", "dog", "walks", "."}, []string{"at", "nn", "vbz", "."}},
+		{[]string{"A", "cat", "runs", "home", "."}, []string{"at", "nn", "vbz", "nr", "."}},
+		{[]string{"Dogs", "run", "in", "1984", "."}, []string{"nns", "vb", "in", "cd", "."}},
+	}
+	for _, tt := range tests {
+		if got := posTagger.TagWords(tt.words); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tagger.TagWords(%v) = %v, want %v", tt.words, got, tt.want)
+		}
+	}
+	if !posTagger.Known("dog") || posTagger.Known("1984") {
+		t.Errorf("Tagger.Known() does not match the training words")
+	}
+}
+
+func TestTrain_deterministic(t *testing.T) {
+	if a, b := trainTest(t), trainTest(t); !reflect.DeepEqual(a, b) {
+		t.Errorf("Train() with the same seed gave different taggers")
+	}
+	if _, err := Train(tagger.NewSliceSentenceReader(nil)); err != tagger.ErrNoTrainingData {
+		t.Errorf("Train() empty error = %v, want %v", err, tagger.ErrNoTrainingData)
+	}
+}
+
+func TestTagger_SaveLoad(t *testing.T) {
+	posTagger := trainTest(t)
+	var saved bytes.Buffer
+	if err := posTagger.Save(&saved); err != nil {
+		t.Fatalf("Tagger.Save() error = %v", err)
+	}
+	loaded, err := Load(bytes.NewReader(saved.Bytes()))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	words := []string{"The", "cats", "walk", "in", "1960", "."}
+	if got, want := loaded.TagWords(words), posTagger.TagWords(words); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded Tagger.TagWords() = %v, want %v", got, want)
+	}
+	if _, err := Load(bytes.NewReader(saved.Bytes()[:saved.Len()/2])); err != ErrBadModel {
+		t.Errorf("Load() truncated error = %v, want %v", err, ErrBadModel)
+	}
+}
+
+func Test_shape(t *testing.T) {
+	for word, want := range map[string]string{"Smith": "Xx", "1960s": "dx", "e-mail": "x-x", "U.S.": "X.X."} {
+		if got := shape(word); got != want {
+			t.Errorf("shape(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
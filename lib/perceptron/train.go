@@ -0,0 +1,219 @@
+package perceptron
+
+import (
+	"io"
+	"math/rand"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// A word seen at least this often, with one tag at least this share of the
+// time, goes into the tag dictionary
+const (
+	tagDictMinCount = 20
+	tagDictMinShare = 0.97
+)
+
+// Option configures the training of a Tagger
+type Option func(*options)
+
+type options struct {
+	iterations int
+	seed       int64
+}
+
+// WithIterations sets how many times training goes over the corpus, 5 by
+// default
+func WithIterations(iterations int) Option {
+	return func(o *options) {
+		o.iterations = iterations
+	}
+}
+
+// WithSeed sets the seed of the shuffle before every iteration, training
+// with the same seed and corpus gives the same Tagger
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// Train learns a Tagger from tagged sentences. Every sentence is held in
+// memory as it is read again on every iteration.
+func Train(sentences tagger.SentenceReader, opts ...Option) (*Tagger, error) {
+	o := options{iterations: 5, seed: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var corpus [][]tagger.TaggedWord
+	for {
+		sentence, err := sentences.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(sentence) > 0 {
+			corpus = append(corpus, sentence)
+		}
+	}
+	if len(corpus) == 0 {
+		return nil, tagger.ErrNoTrainingData
+	}
+
+	train := newTrainer(corpus)
+	random := rand.New(rand.NewSource(o.seed))
+	for iteration := 0; iteration < o.iterations; iteration++ {
+		for _, sentence := range corpus {
+			train.trainSentence(sentence)
+		}
+		random.Shuffle(len(corpus), func(i, j int) {
+			corpus[i], corpus[j] = corpus[j], corpus[i]
+		})
+	}
+	return train.finish(), nil
+}
+
+// The running weights of a feature for one tag. The total sums the weight
+// over every update so far, and is brought up to date from stamp lazily.
+type runningWeight struct {
+	class  int
+	weight float64
+	total  float64
+	stamp  int
+}
+
+type trainer struct {
+	tagger  *Tagger
+	tagIdx  map[string]int
+	weights [][]runningWeight
+	// the number of words trained on so far
+	instances int
+	scores    []float64
+}
+
+func newTrainer(corpus [][]tagger.TaggedWord) *trainer {
+	train := &trainer{
+		tagger: &Tagger{
+			features: make(map[string]int),
+			tagDict:  make(map[string]int),
+			known:    make(map[string]bool),
+		},
+		tagIdx: make(map[string]int),
+	}
+
+	counts := make(map[string]map[int]int)
+	for _, sentence := range corpus {
+		for _, taggedWord := range sentence {
+			tagIndex, ok := train.tagIdx[taggedWord.Tag]
+			if !ok {
+				tagIndex = len(train.tagger.tags)
+				train.tagIdx[taggedWord.Tag] = tagIndex
+				train.tagger.tags = append(train.tagger.tags, taggedWord.Tag)
+			}
+			if counts[taggedWord.Word] == nil {
+				counts[taggedWord.Word] = make(map[int]int)
+			}
+			counts[taggedWord.Word][tagIndex]++
+			train.tagger.known[taggedWord.Word] = true
+		}
+	}
+	for word, tagCounts := range counts {
+		total, best, bestCount := 0, 0, 0
+		for tagIndex, count := range tagCounts {
+			total += count
+			if count > bestCount || count == bestCount && tagIndex < best {
+				best, bestCount = tagIndex, count
+			}
+		}
+		if total >= tagDictMinCount && float64(bestCount)/float64(total) >= tagDictMinShare {
+			train.tagger.tagDict[word] = best
+		}
+	}
+	train.scores = make([]float64, len(train.tagger.tags))
+	return train
+}
+
+// Tags the sentence with the current weights and corrects them where the
+// guess is wrong
+func (train *trainer) trainSentence(sentence []tagger.TaggedWord) {
+	words := make([]string, len(sentence))
+	for i := range sentence {
+		words[i] = sentence[i].Word
+	}
+	context := makeContext(words)
+	prev, prev2 := start[0], start[1]
+	for i, taggedWord := range sentence {
+		guess, ok := train.tagger.tagDict[taggedWord.Word]
+		if !ok {
+			features := getFeatures(i, taggedWord.Word, context, prev, prev2)
+			guess = train.predict(features)
+			train.update(train.tagIdx[taggedWord.Tag], guess, features)
+		}
+		prev2, prev = prev, train.tagger.tags[guess]
+	}
+}
+
+func (train *trainer) predict(features []string) int {
+	scores := train.scores
+	for i := range scores {
+		scores[i] = 0
+	}
+	for _, feature := range features {
+		if index, ok := train.tagger.features[feature]; ok {
+			for _, w := range train.weights[index] {
+				scores[w.class] += w.weight
+			}
+		}
+	}
+	return bestClass(scores, train.tagger.tags)
+}
+
+func (train *trainer) update(truth int, guess int, features []string) {
+	train.instances++
+	if truth == guess {
+		return
+	}
+	for _, feature := range features {
+		index, ok := train.tagger.features[feature]
+		if !ok {
+			index = len(train.weights)
+			train.tagger.features[feature] = index
+			train.weights = append(train.weights, nil)
+		}
+		train.updateWeight(index, truth, 1)
+		train.updateWeight(index, guess, -1)
+	}
+}
+
+func (train *trainer) updateWeight(index int, class int, delta float64) {
+	weights := train.weights[index]
+	for i := range weights {
+		if weights[i].class == class {
+			w := &weights[i]
+			w.total += float64(train.instances-w.stamp) * w.weight
+			w.stamp = train.instances
+			w.weight += delta
+			return
+		}
+	}
+	train.weights[index] = append(weights, runningWeight{class: class, weight: delta, stamp: train.instances})
+}
+
+// Averages every weight over all the updates, which keeps the perceptron
+// from depending on the last few sentences it saw
+func (train *trainer) finish() *Tagger {
+	t := train.tagger
+	t.weights = make([][]classWeight, len(train.weights))
+	for index, weights := range train.weights {
+		for _, w := range weights {
+			total := w.total + float64(train.instances-w.stamp)*w.weight
+			if average := total / float64(train.instances); average != 0 {
+				t.weights[index] = append(t.weights[index], classWeight{Class: w.class, Weight: average})
+			}
+		}
+	}
+	return t
+}
@@ -0,0 +1,32 @@
+package punkt
+
+import (
+	"bytes"
+	_ "embed"
+	"sync"
+)
+
+//go:generate go run ../../cmd/punkt-train -out english.json
+
+// The parameters learned from the Brown Corpus by cmd/punkt-train
+//
+//go:embed english.json
+var englishJSON []byte
+
+var (
+	englishOnce   sync.Once
+	englishParams *Parameters
+)
+
+// English returns a SentenceTokenizer trained on the Brown Corpus. The
+// model is read the first time it is needed and shared after that.
+func English() *SentenceTokenizer {
+	englishOnce.Do(func() {
+		params, err := ReadJSON(bytes.NewReader(englishJSON))
+		if err != nil {
+			panic("punkt: the embedded English model is broken: " + err.Error())
+		}
+		englishParams = params
+	})
+	return NewSentenceTokenizer(englishParams)
+}
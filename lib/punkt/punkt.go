@@ -0,0 +1,277 @@
+// Package punkt splits text into sentences with the unsupervised Punkt
+// algorithm of Kiss and Strunk (2006), as NLTK's PunktSentenceTokenizer does.
+// A Trainer learns abbreviations, collocations and frequent sentence starters
+// from raw text, and a SentenceTokenizer uses them to decide which periods end
+// a sentence. English returns a tokenizer trained on the Brown Corpus.
+package punkt
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// The orthographic context a type has been seen in: upper or lower case, at
+// the beginning of a sentence, in the middle of one or where it is unknown
+const (
+	orthoBegUC = 1 << iota
+	orthoMidUC
+	orthoUnkUC
+	orthoBegLC
+	orthoMidLC
+	orthoUnkLC
+
+	orthoUC = orthoBegUC | orthoMidUC | orthoUnkUC
+	orthoLC = orthoBegLC | orthoMidLC | orthoUnkLC
+)
+
+// Parameters are what a Trainer learns and a SentenceTokenizer uses. Types
+// are lower case and without their final period.
+type Parameters struct {
+	// AbbrevTypes are words that are followed by a period which does not end
+	// the sentence, like mr or u.s
+	AbbrevTypes map[string]bool
+	// Collocations are pairs of words that stay in one sentence even though
+	// the first ends with a period, like a date
+	Collocations map[[2]string]bool
+	// SentStarters are words that often start a sentence, a period before
+	// them ends the sentence even after an abbreviation
+	SentStarters map[string]bool
+	// OrthoContext records the case of every type at the start of sentences
+	// and in them
+	OrthoContext map[string]int
+}
+
+// NewParameters returns empty Parameters
+func NewParameters() *Parameters {
+	return &Parameters{
+		AbbrevTypes:  make(map[string]bool),
+		Collocations: make(map[[2]string]bool),
+		SentStarters: make(map[string]bool),
+		OrthoContext: make(map[string]int),
+	}
+}
+
+// The Parameters as they are written to JSON, sorted so a model file only
+// changes when the model does
+type jsonParameters struct {
+	AbbrevTypes  []string       `json:"abbrev_types"`
+	Collocations [][2]string    `json:"collocations"`
+	SentStarters []string       `json:"sent_starters"`
+	OrthoContext map[string]int `json:"ortho_context"`
+}
+
+// WriteJSON writes the parameters to w
+func (p *Parameters) WriteJSON(w io.Writer) error {
+	out := jsonParameters{
+		AbbrevTypes:  sortedKeys(p.AbbrevTypes),
+		Collocations: make([][2]string, 0, len(p.Collocations)),
+		SentStarters: sortedKeys(p.SentStarters),
+		OrthoContext: p.OrthoContext,
+	}
+	for collocation := range p.Collocations {
+		out.Collocations = append(out.Collocations, collocation)
+	}
+	sort.Slice(out.Collocations, func(i, j int) bool {
+		a, b := out.Collocations[i], out.Collocations[j]
+		return a[0] < b[0] || a[0] == b[0] && a[1] < b[1]
+	})
+	return json.NewEncoder(w).Encode(out)
+}
+
+// ReadJSON reads parameters written by WriteJSON
+func ReadJSON(r io.Reader) (*Parameters, error) {
+	var in jsonParameters
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, err
+	}
+	p := NewParameters()
+	for _, typ := range in.AbbrevTypes {
+		p.AbbrevTypes[typ] = true
+	}
+	for _, collocation := range in.Collocations {
+		p.Collocations[collocation] = true
+	}
+	for _, typ := range in.SentStarters {
+		p.SentStarters[typ] = true
+	}
+	for typ, ortho := range in.OrthoContext {
+		p.OrthoContext[typ] = ortho
+	}
+	return p, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Sentence is one sentence of the text. The ends are exclusive so
+// text[ByteStart:ByteEnd] is the sentence.
+type Sentence struct {
+	Text      string
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+}
+
+// SentenceTokenizer splits text into sentences
+type SentenceTokenizer struct {
+	params *Parameters
+}
+
+// NewSentenceTokenizer returns a SentenceTokenizer using the given parameters
+func NewSentenceTokenizer(params *Parameters) *SentenceTokenizer {
+	return &SentenceTokenizer{params: params}
+}
+
+// Parameters returns the parameters the tokenizer uses
+func (st *SentenceTokenizer) Parameters() *Parameters {
+	return st.params
+}
+
+// Tokenize splits the text into sentences
+func (st *SentenceTokenizer) Tokenize(text string) []string {
+	sentences := st.Sentences(text)
+	texts := make([]string, len(sentences))
+	for i := range sentences {
+		texts[i] = sentences[i].Text
+	}
+	return texts
+}
+
+// Sentences splits the text into sentences with their offsets. The space
+// between sentences belongs to neither.
+func (st *SentenceTokenizer) Sentences(text string) []Sentence {
+	tokens := tokenize(text)
+	annotateFirstPass(tokens, st.params)
+	for i := 0; i+1 < len(tokens); i++ {
+		st.annotateSecondPass(&tokens[i], &tokens[i+1])
+	}
+
+	sentences := make([]Sentence, 0)
+	start := -1
+	for i := 0; i < len(tokens); i++ {
+		if start < 0 {
+			start = tokens[i].start
+		}
+		if !tokens[i].sentBreak && i+1 < len(tokens) {
+			continue
+		}
+		// closing quotes and brackets right after the break are still part
+		// of the sentence
+		end := tokens[i].end
+		for i+1 < len(tokens) && tokens[i+1].start == end && isClosing(tokens[i+1].tok) {
+			i++
+			end = tokens[i].end
+		}
+		sentences = append(sentences, Sentence{Text: text[start:end], ByteStart: start, ByteEnd: end})
+		start = -1
+	}
+
+	currByte, currRune := 0, 0
+	for i := range sentences {
+		currRune += utf8.RuneCountInString(text[currByte:sentences[i].ByteStart])
+		sentences[i].RuneStart = currRune
+		currRune += utf8.RuneCountInString(sentences[i].Text)
+		sentences[i].RuneEnd = currRune
+		currByte = sentences[i].ByteEnd
+	}
+	return sentences
+}
+
+func isClosing(tok string) bool {
+	return strings.Trim(tok, "\"')]}”’»") == ""
+}
+
+// Marks the tokens that end a sentence and the abbreviations using only
+// the type of each token
+func annotateFirstPass(tokens []token, params *Parameters) {
+	for i := range tokens {
+		tok := &tokens[i]
+		tok.sentBreak, tok.abbr, tok.ellipsis = false, false, false
+		switch {
+		case strings.Contains(sentEndChars, tok.tok) && len(tok.tok) == 1:
+			tok.sentBreak = true
+		case tok.isEllipsis():
+			tok.ellipsis = true
+		case tok.periodFinal() && !strings.HasSuffix(tok.tok, ".."):
+			typ := tok.typeNoPeriod()
+			if params.AbbrevTypes[typ] || params.AbbrevTypes[typ[strings.LastIndex(typ, "-")+1:]] {
+				tok.abbr = true
+			} else {
+				tok.sentBreak = true
+			}
+		}
+	}
+}
+
+// Looks at the token after a period to correct the first pass: collocations
+// and initials do not end sentences, abbreviations before a word that starts
+// sentences do
+func (st *SentenceTokenizer) annotateSecondPass(tok *token, next *token) {
+	if !tok.periodFinal() {
+		return
+	}
+	typ := tok.typeNoPeriod()
+	nextTyp := next.typeNoSentPeriod()
+	isInitial := tok.isInitial()
+
+	if st.params.Collocations[[2]string{typ, nextTyp}] {
+		tok.sentBreak = false
+		tok.abbr = true
+		return
+	}
+
+	if (tok.abbr || tok.ellipsis) && !isInitial {
+		if st.orthoHeuristic(next) == isStarter {
+			tok.sentBreak = true
+			return
+		}
+		if next.firstUpper() && st.params.SentStarters[nextTyp] {
+			tok.sentBreak = true
+			return
+		}
+	}
+
+	if isInitial || typ == numberType {
+		starter := st.orthoHeuristic(next)
+		if starter == notStarter {
+			tok.sentBreak = false
+			tok.abbr = true
+			return
+		}
+		if starter == unknownStarter && isInitial && next.firstUpper() && st.params.OrthoContext[nextTyp]&orthoLC == 0 {
+			tok.sentBreak = false
+			tok.abbr = true
+		}
+	}
+}
+
+const (
+	unknownStarter = iota
+	isStarter
+	notStarter
+)
+
+// Decides from the case a word has been seen in whether it starts a sentence
+func (st *SentenceTokenizer) orthoHeuristic(tok *token) int {
+	if strings.Contains(";:,.!?", tok.tok) {
+		return notStarter
+	}
+	ortho := st.params.OrthoContext[tok.typeNoSentPeriod()]
+	if tok.firstUpper() && ortho&orthoLC != 0 && ortho&orthoMidUC == 0 {
+		return isStarter
+	}
+	if tok.firstLower() && (ortho&orthoUC != 0 || ortho&orthoBegLC == 0) {
+		return notStarter
+	}
+	return unknownStarter
+}
@@ -0,0 +1,80 @@
+package punkt
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEnglish_Tokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"abbreviations", "Mr. Smith went to Washington. He arrived at 5 p.m. on Friday.",
+			[]string{"Mr. Smith went to Washington.", "He arrived at 5 p.m. on Friday."}},
+		{"quotes", `"Is it done?" she asked. "Yes." The U.S. Senate voted.`,
+			[]string{`"Is it done?"`, "she asked.", `"Yes."`, "The U.S. Senate voted."}},
+		{"initials", "The poem is by T. S. Eliot. It is long!",
+			[]string{"The poem is by T. S. Eliot.", "It is long!"}},
+		{"ellipsis", "Wait... what happened? Nothing.",
+			[]string{"Wait... what happened?", "Nothing."}},
+		{"no period", "no end in sight", []string{"no end in sight"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := English().Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("English().Tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentenceTokenizer_Sentences(t *testing.T) {
+	text := "  Café owners rejoiced.\n\nNaïve ones didn't. "
+	sentences := English().Sentences(text)
+	if len(sentences) != 2 {
+		t.Fatalf("Sentences() = %+v", sentences)
+	}
+	for _, sentence := range sentences {
+		if text[sentence.ByteStart:sentence.ByteEnd] != sentence.Text {
+			t.Errorf("sentence %q has byte offsets %d:%d", sentence.Text, sentence.ByteStart, sentence.ByteEnd)
+		}
+		if sentence.RuneStart != utf8.RuneCountInString(text[:sentence.ByteStart]) || sentence.RuneEnd-sentence.RuneStart != utf8.RuneCountInString(sentence.Text) {
+			t.Errorf("sentence %q has rune offsets %d:%d", sentence.Text, sentence.RuneStart, sentence.RuneEnd)
+		}
+	}
+}
+
+func TestTrainer(t *testing.T) {
+	trainer := NewTrainer()
+	text := strings.Repeat("We met Dr. Brown at the lab. The lab results were good and the good news spread. ", 20)
+	trainer.Train(text)
+	params := trainer.Parameters()
+	if !params.AbbrevTypes["dr"] {
+		t.Errorf("Trainer did not learn dr as an abbreviation: %v", params.AbbrevTypes)
+	}
+	if params.AbbrevTypes["lab"] || params.AbbrevTypes["good"] {
+		t.Errorf("Trainer learned a sentence end as an abbreviation: %v", params.AbbrevTypes)
+	}
+
+	var buf bytes.Buffer
+	if err := params.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, params) {
+		t.Errorf("ReadJSON() = %+v, want %+v", loaded, params)
+	}
+	got := NewSentenceTokenizer(loaded).Tokenize("We met Dr. Brown. The lab was closed.")
+	want := []string{"We met Dr. Brown.", "The lab was closed."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %q, want %q", got, want)
+	}
+}
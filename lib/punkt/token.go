@@ -0,0 +1,170 @@
+package punkt
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	numberPattern  = regexp.MustCompile(`^-?[.,]?\d[\d,.-]*\.?$`)
+	initialPattern = regexp.MustCompile(`^[^\W\d]\.$`)
+	alphaPattern   = regexp.MustCompile(`^[^\W\d]+$`)
+	letterPattern  = regexp.MustCompile(`[^\W\d]`)
+)
+
+// numberType is the type every number shares
+const numberType = "##number##"
+
+// Punctuation split off the front and the back of words. Periods stay on
+// the word, that is what the segmenter decides about.
+const (
+	openingPunct = "\"'`([{¿¡“‘«"
+	closingPunct = "\"'`)]}:;,!?”’»"
+	// punctuation that ends a sentence as a token of its own
+	sentEndChars = ".?!"
+	// punctuation that can not start a sentence
+	internalPunct = ",:;"
+)
+
+// A word of the text with what the segmenter learned about it
+type token struct {
+	tok   string
+	start int
+	end   int
+	// first after a blank line
+	paraStart bool
+
+	sentBreak bool
+	abbr      bool
+	ellipsis  bool
+}
+
+// The type of a token is its lower case form, all numbers share one type
+func (t *token) typ() string {
+	if numberPattern.MatchString(t.tok) {
+		return numberType
+	}
+	return strings.ToLower(t.tok)
+}
+
+// The type without a final period
+func (t *token) typeNoPeriod() string {
+	typ := t.typ()
+	if len(typ) > 1 && strings.HasSuffix(typ, ".") {
+		return typ[:len(typ)-1]
+	}
+	return typ
+}
+
+// The type without the period when the period ends a sentence
+func (t *token) typeNoSentPeriod() string {
+	if t.sentBreak {
+		return t.typeNoPeriod()
+	}
+	return t.typ()
+}
+
+func (t *token) periodFinal() bool {
+	return strings.HasSuffix(t.tok, ".")
+}
+
+func (t *token) firstUpper() bool {
+	r, _ := utf8.DecodeRuneInString(t.tok)
+	return unicode.IsUpper(r)
+}
+
+func (t *token) firstLower() bool {
+	r, _ := utf8.DecodeRuneInString(t.tok)
+	return unicode.IsLower(r)
+}
+
+func (t *token) isEllipsis() bool {
+	return len(t.tok) > 1 && strings.Trim(t.tok, ".") == ""
+}
+
+func (t *token) isNumber() bool {
+	return t.typ() == numberType
+}
+
+func (t *token) isInitial() bool {
+	return initialPattern.MatchString(t.tok)
+}
+
+func (t *token) isAlpha() bool {
+	return alphaPattern.MatchString(t.tok)
+}
+
+func (t *token) isNonPunct() bool {
+	return letterPattern.MatchString(t.typ())
+}
+
+// Splits text into the tokens the segmenter works on: runs of non space with
+// the punctuation around them split off, and ellipses split from the word
+// before them
+func tokenize(text string) []token {
+	tokens := make([]token, 0)
+	newlines := 0
+	i := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			if r == '\n' {
+				newlines++
+			}
+			i += size
+			continue
+		}
+		end := i
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+		chunk := splitChunk(text, i, end)
+		chunk[0].paraStart = newlines > 1 || len(tokens) == 0
+		tokens = append(tokens, chunk...)
+		newlines = 0
+		i = end
+	}
+	return tokens
+}
+
+// Splits the run of non space text[start:end] into tokens
+func splitChunk(text string, start int, end int) []token {
+	var leading, trailing []token
+	for start < end {
+		r, size := utf8.DecodeRuneInString(text[start:end])
+		if !strings.ContainsRune(openingPunct, r) {
+			break
+		}
+		leading = append(leading, token{tok: text[start : start+size], start: start, end: start + size})
+		start += size
+	}
+	for start < end {
+		r, size := utf8.DecodeLastRuneInString(text[start:end])
+		if r == '.' {
+			// the period of "word". ends the sentence, not the word
+			if prev, _ := utf8.DecodeLastRuneInString(text[start : end-size]); !strings.ContainsRune(closingPunct, prev) {
+				break
+			}
+		} else if !strings.ContainsRune(closingPunct, r) {
+			break
+		}
+		trailing = append([]token{{tok: text[end-size : end], start: end - size, end: end}}, trailing...)
+		end -= size
+	}
+	if start < end {
+		// word... is the word and an ellipsis
+		dots := end - start - len(strings.TrimRight(text[start:end], "."))
+		if dots > 1 && dots < end-start {
+			trailing = append([]token{{tok: text[end-dots : end], start: end - dots, end: end}}, trailing...)
+			end -= dots
+		}
+		leading = append(leading, token{tok: text[start:end], start: start, end: end})
+	}
+	return append(leading, trailing...)
+}
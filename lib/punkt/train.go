@@ -0,0 +1,245 @@
+package punkt
+
+import (
+	"math"
+	"strings"
+)
+
+// The thresholds NLTK's PunktTrainer uses
+const (
+	// score a type needs to be an abbreviation
+	abbrevThreshold = 0.3
+	// types seen less often than this are rare, a period after a rare type
+	// followed by a lower case word is taken to be an abbreviation
+	abbrevBackoff = 5
+	// log likelihood a pair of types needs to be a collocation
+	collocationThreshold = 7.88
+	// log likelihood a type needs to be a sentence starter
+	sentStarterThreshold = 30
+	// a collocation has to be seen more often than this
+	minCollocFreq = 1
+)
+
+// Trainer learns Parameters from raw text
+type Trainer struct {
+	params *Parameters
+	tokens []token
+
+	typeCounts     map[string]int
+	numTokens      int
+	numPeriodToks  int
+	sentBreakCount int
+	starterCounts  map[string]int
+	collocCounts   map[[2]string]int
+	finalized      bool
+}
+
+// NewTrainer returns a Trainer that has not seen any text
+func NewTrainer() *Trainer {
+	return &Trainer{
+		params:        NewParameters(),
+		typeCounts:    make(map[string]int),
+		starterCounts: make(map[string]int),
+		collocCounts:  make(map[[2]string]int),
+	}
+}
+
+// Train adds the text to what the trainer has seen. Texts are taken to be
+// separate documents.
+func (t *Trainer) Train(text string) {
+	tokens := tokenize(text)
+	for i := range tokens {
+		t.typeCounts[tokens[i].typ()]++
+		t.numTokens++
+		if tokens[i].periodFinal() {
+			t.numPeriodToks++
+		}
+	}
+	t.tokens = append(t.tokens, tokens...)
+	t.finalized = false
+}
+
+// Parameters learns the parameters from all the text seen so far
+func (t *Trainer) Parameters() *Parameters {
+	if !t.finalized {
+		t.finalize()
+	}
+	return t.params
+}
+
+func (t *Trainer) finalize() {
+	params := NewParameters()
+	t.params = params
+	t.findAbbrevTypes()
+
+	annotateFirstPass(t.tokens, params)
+	t.findOrthoContext()
+
+	t.sentBreakCount = 0
+	t.starterCounts = make(map[string]int)
+	t.collocCounts = make(map[[2]string]int)
+	for i := range t.tokens {
+		tok := &t.tokens[i]
+		if tok.sentBreak {
+			t.sentBreakCount++
+		}
+		if !tok.periodFinal() || i+1 == len(t.tokens) {
+			continue
+		}
+		next := &t.tokens[i+1]
+		if t.isRareAbbrevType(tok, next) {
+			params.AbbrevTypes[tok.typeNoPeriod()] = true
+		}
+		if tok.sentBreak && !tok.isNumber() && !tok.isInitial() && next.isAlpha() {
+			t.starterCounts[next.typ()]++
+		}
+		if (tok.abbr || tok.sentBreak && (tok.isNumber() || tok.isInitial())) && tok.isNonPunct() && next.isNonPunct() {
+			t.collocCounts[[2]string{tok.typeNoPeriod(), next.typeNoSentPeriod()}]++
+		}
+	}
+
+	t.findSentStarters()
+	t.findCollocations()
+	t.finalized = true
+}
+
+// Scores every type seen with a final period by how strongly it goes with
+// the period, how short it is and how many periods it has inside
+func (t *Trainer) findAbbrevTypes() {
+	for typ := range t.typeCounts {
+		if !strings.HasSuffix(typ, ".") || typ == numberType || !letterPattern.MatchString(typ) {
+			continue
+		}
+		typ = typ[:len(typ)-1]
+		numPeriods := strings.Count(typ, ".") + 1
+		numNonPeriods := len([]rune(typ)) - numPeriods + 1
+		countWithPeriod := t.typeCounts[typ+"."]
+		countWithoutPeriod := t.typeCounts[typ]
+
+		ll := dunningLogLikelihood(float64(countWithPeriod+countWithoutPeriod), float64(t.numPeriodToks), float64(countWithPeriod), float64(t.numTokens))
+		fLength := math.Exp(-float64(numNonPeriods))
+		fPenalty := math.Pow(float64(numNonPeriods), -float64(countWithoutPeriod))
+		if ll*fLength*float64(numPeriods)*fPenalty >= abbrevThreshold {
+			t.params.AbbrevTypes[typ] = true
+		}
+	}
+}
+
+// Records the case every type is seen in at the start of sentences and in
+// them, as far as the first pass knows where sentences start
+func (t *Trainer) findOrthoContext() {
+	const (
+		initial = iota
+		internal
+		unknown
+	)
+	context := internal
+	for i := range t.tokens {
+		tok := &t.tokens[i]
+		if tok.paraStart && context != unknown {
+			context = initial
+		}
+		typ := tok.typeNoSentPeriod()
+		switch {
+		case tok.firstUpper():
+			t.params.OrthoContext[typ] |= [...]int{orthoBegUC, orthoMidUC, orthoUnkUC}[context]
+		case tok.firstLower():
+			t.params.OrthoContext[typ] |= [...]int{orthoBegLC, orthoMidLC, orthoUnkLC}[context]
+		}
+
+		switch {
+		case tok.sentBreak && (tok.isNumber() || tok.isInitial()):
+			context = unknown
+		case tok.sentBreak:
+			context = initial
+		case tok.ellipsis || tok.abbr:
+			context = unknown
+		default:
+			context = internal
+		}
+	}
+}
+
+// A short type that was taken to end a sentence is an abbreviation when the
+// next word can not start one
+func (t *Trainer) isRareAbbrevType(tok *token, next *token) bool {
+	if tok.abbr || !tok.sentBreak {
+		return false
+	}
+	typ := tok.typeNoSentPeriod()
+	count := t.typeCounts[typ] + t.typeCounts[typ+"."]
+	if t.params.AbbrevTypes[typ] || count >= abbrevBackoff {
+		return false
+	}
+	if strings.ContainsAny(next.tok[:1], internalPunct) {
+		return true
+	}
+	if next.firstLower() {
+		ortho := t.params.OrthoContext[next.typeNoSentPeriod()]
+		return ortho&orthoBegUC != 0 && ortho&orthoMidUC == 0
+	}
+	return false
+}
+
+// Types that follow sentence breaks far more often than chance
+func (t *Trainer) findSentStarters() {
+	if t.sentBreakCount == 0 {
+		return
+	}
+	for typ, atBreak := range t.starterCounts {
+		count := t.typeCounts[typ] + t.typeCounts[typ+"."]
+		if count < atBreak {
+			continue
+		}
+		ll := colLogLikelihood(float64(t.sentBreakCount), float64(count), float64(atBreak), float64(t.numTokens))
+		if ll >= sentStarterThreshold && float64(t.numTokens)/float64(t.sentBreakCount) > float64(count)/float64(atBreak) {
+			t.params.SentStarters[typ] = true
+		}
+	}
+}
+
+// Pairs of types around a period seen together far more often than chance
+func (t *Trainer) findCollocations() {
+	for pair, together := range t.collocCounts {
+		if t.params.SentStarters[pair[1]] {
+			continue
+		}
+		count1 := t.typeCounts[pair[0]] + t.typeCounts[pair[0]+"."]
+		count2 := t.typeCounts[pair[1]] + t.typeCounts[pair[1]+"."]
+		if count1 <= 1 || count2 <= 1 || together <= minCollocFreq || together > count1 || together > count2 {
+			continue
+		}
+		ll := colLogLikelihood(float64(count1), float64(count2), float64(together), float64(t.numTokens))
+		if ll >= collocationThreshold && float64(t.numTokens)/float64(count1) > float64(count2)/float64(together) {
+			t.params.Collocations[pair] = true
+		}
+	}
+}
+
+// Dunning's log likelihood of a type and the period, against the
+// hypothesis that the type is almost always followed by a period
+func dunningLogLikelihood(countA, countB, countAB, n float64) float64 {
+	p1 := countB / n
+	p2 := 0.99
+	nullHypo := countAB*math.Log(p1) + (countA-countAB)*math.Log(1-p1)
+	altHypo := countAB*math.Log(p2) + (countA-countAB)*math.Log(1-p2)
+	return -2 * (nullHypo - altHypo)
+}
+
+// Dunning's log likelihood that two types are not independent
+func colLogLikelihood(countA, countB, countAB, n float64) float64 {
+	p := countB / n
+	p1 := countAB / countA
+	p2 := (countB - countAB) / (n - countA)
+
+	summand1 := countAB*math.Log(p) + (countA-countAB)*math.Log(1-p)
+	summand2 := (countB-countAB)*math.Log(p) + (n-countA-countB+countAB)*math.Log(1-p)
+	summand3, summand4 := 0.0, 0.0
+	if countA != countAB {
+		summand3 = countAB*math.Log(p1) + (countA-countAB)*math.Log(1-p1)
+	}
+	if countB != countAB {
+		summand4 = (countB-countAB)*math.Log(p2) + (n-countA-countB+countAB)*math.Log(1-p2)
+	}
+	return -2 * (summand1 + summand2 - summand3 - summand4)
+}
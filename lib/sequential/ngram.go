@@ -0,0 +1,150 @@
+package sequential
+
+import (
+	"io"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// Unigram tags a word with the tag it has most often, looked up in a table
+// learned from a corpus or given as a lexicon
+type Unigram struct {
+	model   map[string]string
+	backoff Tagger
+}
+
+// NewUnigram returns a Unigram tagger for the words of the lexicon, the
+// other words go to backoff which may be nil
+func NewUnigram(lexicon map[string]string, backoff Tagger) *Unigram {
+	model := make(map[string]string, len(lexicon))
+	for word, tag := range lexicon {
+		model[word] = tag
+	}
+	return &Unigram{model: model, backoff: backoff}
+}
+
+// TrainUnigram learns the most frequent tag of every word. A word is only
+// learned when its tag was seen more than cutoff times and the backoff
+// tagger would not already get it right.
+func TrainUnigram(sentences tagger.SentenceReader, backoff Tagger, cutoff int) (*Unigram, error) {
+	model, err := train(sentences, backoff, cutoff, func(words []string, index int, history []string) string {
+		return words[index]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Unigram{model: model, backoff: backoff}, nil
+}
+
+// ChooseTag looks the word up
+func (t *Unigram) ChooseTag(words []string, index int, history []string) (string, bool) {
+	tag, ok := t.model[words[index]]
+	return tag, ok
+}
+
+// Backoff returns the tagger for the words not in the table
+func (t *Unigram) Backoff() Tagger {
+	return t.backoff
+}
+
+// TagWords tags the words with the chain
+func (t *Unigram) TagWords(words []string) []string {
+	return tagWords(t, words)
+}
+
+// Bigram tags a word with the tag it has most often after the tag of the
+// word before it
+type Bigram struct {
+	model   map[string]string
+	backoff Tagger
+}
+
+// TrainBigram learns the most frequent tag of every word after every tag,
+// with the same cutoff and backoff rules as TrainUnigram
+func TrainBigram(sentences tagger.SentenceReader, backoff Tagger, cutoff int) (*Bigram, error) {
+	model, err := train(sentences, backoff, cutoff, bigramContext)
+	if err != nil {
+		return nil, err
+	}
+	return &Bigram{model: model, backoff: backoff}, nil
+}
+
+// The tag before and the word, the first word of a sentence follows an
+// empty tag
+func bigramContext(words []string, index int, history []string) string {
+	prev := ""
+	if index > 0 {
+		prev = history[index-1]
+	}
+	return prev + "\x00" + words[index]
+}
+
+// ChooseTag looks up the word after the tag before it
+func (t *Bigram) ChooseTag(words []string, index int, history []string) (string, bool) {
+	tag, ok := t.model[bigramContext(words, index, history)]
+	return tag, ok
+}
+
+// Backoff returns the tagger for the contexts not in the table
+func (t *Bigram) Backoff() Tagger {
+	return t.backoff
+}
+
+// TagWords tags the words with the chain
+func (t *Bigram) TagWords(words []string) []string {
+	return tagWords(t, words)
+}
+
+// Counts the tags of every context and keeps the most frequent one of the
+// contexts where it is worth it, as NLTK's ContextTagger does
+func train(sentences tagger.SentenceReader, backoff Tagger, cutoff int, context func([]string, int, []string) string) (map[string]string, error) {
+	counts := make(map[string]map[string]int)
+	useful := make(map[string]bool)
+	read := false
+	for {
+		sentence, err := sentences.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		read = true
+		words := make([]string, len(sentence))
+		tags := make([]string, len(sentence))
+		for i := range sentence {
+			words[i], tags[i] = sentence[i].Word, sentence[i].Tag
+		}
+		var backoffTags []string
+		if backoff != nil {
+			backoffTags = backoff.TagWords(words)
+		}
+		for index := range words {
+			key := context(words, index, tags)
+			if counts[key] == nil {
+				counts[key] = make(map[string]int)
+			}
+			counts[key][tags[index]]++
+			if backoffTags == nil || backoffTags[index] != tags[index] {
+				useful[key] = true
+			}
+		}
+	}
+	if !read {
+		return nil, tagger.ErrNoTrainingData
+	}
+
+	model := make(map[string]string)
+	for key := range useful {
+		best, hits := "", 0
+		for tag, count := range counts[key] {
+			if count > hits || count == hits && tag < best {
+				best, hits = tag, count
+			}
+		}
+		if hits > cutoff {
+			model[key] = best
+		}
+	}
+	return model, nil
+}
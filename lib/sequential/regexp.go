@@ -0,0 +1,51 @@
+package sequential
+
+import "regexp"
+
+// Rule tags the words matching the regular expression Pattern with Tag
+type Rule struct {
+	Pattern string
+	Tag     string
+}
+
+// Regexp tags a word with the tag of the first rule it matches
+type Regexp struct {
+	patterns []*regexp.Regexp
+	tags     []string
+	backoff  Tagger
+}
+
+// NewRegexp returns a Regexp tagger trying the rules in order, the words no
+// rule matches go to backoff which may be nil
+func NewRegexp(rules []Rule, backoff Tagger) (*Regexp, error) {
+	t := &Regexp{backoff: backoff}
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		t.patterns = append(t.patterns, pattern)
+		t.tags = append(t.tags, rule.Tag)
+	}
+	return t, nil
+}
+
+// ChooseTag returns the tag of the first rule the word matches
+func (t *Regexp) ChooseTag(words []string, index int, history []string) (string, bool) {
+	for i, pattern := range t.patterns {
+		if pattern.MatchString(words[index]) {
+			return t.tags[i], true
+		}
+	}
+	return "", false
+}
+
+// Backoff returns the tagger for the words no rule matches
+func (t *Regexp) Backoff() Tagger {
+	return t.backoff
+}
+
+// TagWords tags the words with the chain
+func (t *Regexp) TagWords(words []string) []string {
+	return tagWords(t, words)
+}
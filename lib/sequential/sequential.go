@@ -0,0 +1,98 @@
+// Package sequential has simple taggers that tag a sentence one word at a
+// time, like NLTK's SequentialBackoffTagger family. Each tagger either
+// decides on a tag or leaves the word to its backoff tagger, so they can be
+// chained with the most specific tagger first:
+//
+//	hmm, _ := tagger.LoadFile("brown.model")
+//	lexicon := sequential.NewUnigram(map[string]string{"GPU": "nn"}, sequential.Wrap(hmm))
+//	rules, _ := sequential.NewRegexp([]sequential.Rule{{`^\d+$`, "cd"}}, lexicon)
+package sequential
+
+import (
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+// Tagger tags a sentence a word at a time. ChooseTag is given the tags of
+// the words before index and returns false when it can not decide, the word
+// is then tagged by the Backoff tagger. TagWords tags a whole sentence that
+// way, down the chain.
+type Tagger interface {
+	tagger.SequenceTagger
+	ChooseTag(words []string, index int, history []string) (string, bool)
+	Backoff() Tagger
+}
+
+// tagWords tags every word with the first tagger of the chain starting at t
+// that decides on a tag. Words no tagger decides on get an empty tag.
+func tagWords(t Tagger, words []string) []string {
+	tags := make([]string, len(words))
+	// a wrapped tagger tags the whole sentence at once, the tags are kept
+	// for the other words
+	sentenceTags := make(map[*wrapped][]string)
+	for index := range words {
+		for current := t; current != nil; current = current.Backoff() {
+			if w, ok := current.(*wrapped); ok {
+				if sentenceTags[w] == nil {
+					sentenceTags[w] = w.sequenceTagger.TagWords(words)
+				}
+				tags[index] = sentenceTags[w][index]
+				break
+			}
+			if tag, ok := current.ChooseTag(words, index, tags[:index]); ok {
+				tags[index] = tag
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// Default gives every word the same tag, it is the usual end of a chain
+type Default struct {
+	tag string
+}
+
+// NewDefault returns a tagger that tags every word with tag
+func NewDefault(tag string) *Default {
+	return &Default{tag: tag}
+}
+
+// ChooseTag always returns the tag
+func (t *Default) ChooseTag(words []string, index int, history []string) (string, bool) {
+	return t.tag, true
+}
+
+// Backoff is always nil, Default decides on every word
+func (t *Default) Backoff() Tagger {
+	return nil
+}
+
+// TagWords tags every word with the tag
+func (t *Default) TagWords(words []string) []string {
+	return tagWords(t, words)
+}
+
+// A statistical tagger like the HMM or the perceptron in a chain, it tags
+// whole sentences and decides on every word
+type wrapped struct {
+	sequenceTagger tagger.SequenceTagger
+}
+
+// Wrap returns a Tagger that decides on every word with the tags the
+// sequence tagger gives the whole sentence. Put it at the end of a chain to
+// back off to a statistical model.
+func Wrap(sequenceTagger tagger.SequenceTagger) Tagger {
+	return &wrapped{sequenceTagger: sequenceTagger}
+}
+
+func (t *wrapped) ChooseTag(words []string, index int, history []string) (string, bool) {
+	return t.sequenceTagger.TagWords(words)[index], true
+}
+
+func (t *wrapped) Backoff() Tagger {
+	return nil
+}
+
+func (t *wrapped) TagWords(words []string) []string {
+	return t.sequenceTagger.TagWords(words)
+}
@@ -0,0 +1,80 @@
+package sequential
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modquiz/go-nltb/lib/tagger"
+)
+
+const testCorpus = "The/at dog/nn walks/vbz ./.\n" +
+	"The/at walks/nns end/vb ./.\n" +
+	"A/at dog/nn can/md walk/vb ./.\n" +
+	"The/at can/nn is/bez empty/jj ./.\n"
+
+func corpus() tagger.SentenceReader {
+	return tagger.NewSentenceReader(strings.NewReader(testCorpus))
+}
+
+// tags every sentence the same no matter the words
+type fixedTagger []string
+
+func (f fixedTagger) TagWords(words []string) []string {
+	return f[:len(words)]
+}
+
+func TestChain(t *testing.T) {
+	unigram, err := TrainUnigram(corpus(), NewDefault("nn"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bigram, err := TrainBigram(corpus(), unigram, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := NewRegexp([]Rule{{Pattern: `^\d+$`, Tag: "cd"}, {Pattern: `ing$`, Tag: "vbg"}}, bigram)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tagger Tagger
+		words  []string
+		want   []string
+	}{
+		{"default", NewDefault("nn"), []string{"a", "b"}, []string{"nn", "nn"}},
+		{"unigram", unigram, []string{"The", "dog", "walks", "home"}, []string{"at", "nn", "nns", "nn"}},
+		{"bigram", bigram, []string{"The", "dog", "walks", "home"}, []string{"at", "nn", "vbz", "nn"}},
+		{"bigram context", bigram, []string{"The", "can", "walks", "."}, []string{"at", "nn", "vbz", "."}},
+		{"bigram after md", bigram, []string{"dog", "can", "walk", "."}, []string{"nn", "md", "vb", "."}},
+		{"regexp", rules, []string{"The", "dog", "walking", "12"}, []string{"at", "nn", "vbg", "cd"}},
+		{"lexicon before a model", NewUnigram(map[string]string{"GPU": "np"}, Wrap(fixedTagger{"at", "nn", "vbz"})), []string{"The", "GPU", "runs"}, []string{"at", "np", "vbz"}},
+		{"no backoff", NewUnigram(map[string]string{"a": "at"}, nil), []string{"a", "b"}, []string{"at", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tagger.TagWords(tt.words); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TagWords(%v) = %v, want %v", tt.words, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrainUnigram_backoff(t *testing.T) {
+	// words the backoff already tags right are not learned
+	unigram, err := TrainUnigram(corpus(), NewDefault("at"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := unigram.model["The"]; ok {
+		t.Errorf("TrainUnigram() learned a word the backoff tags right")
+	}
+	if _, err := TrainUnigram(tagger.NewSliceSentenceReader(nil), nil, 0); err != tagger.ErrNoTrainingData {
+		t.Errorf("TrainUnigram() empty error = %v, want %v", err, tagger.ErrNoTrainingData)
+	}
+	if _, err := NewRegexp([]Rule{{Pattern: "(", Tag: "x"}}, nil); err == nil {
+		t.Errorf("NewRegexp() accepted a bad pattern")
+	}
+}
@@ -0,0 +1,67 @@
+package tagger
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/modquiz/go-nltb/brown"
+)
+
+// Asset returns the contents of the embedded Brown Corpus file with the given name
+func Asset(name string) ([]byte, error) {
+	return brown.Files.ReadFile(name)
+}
+
+// AssetNames returns the names of every embedded Brown Corpus file
+func AssetNames() []string {
+	names, _ := CorpusFiles(brown.Files, ".")
+	return names
+}
+
+// CorpusFiles lists the corpus files below dir in name order. Hidden files,
+// Go source and READMEs sit next to corpus files but are not part of the
+// corpus.
+func CorpusFiles(fsys fs.FS, dir string) ([]string, error) {
+	names := make([]string, 0)
+	err := fs.WalkDir(fsys, dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		base := path.Base(name)
+		if name != dir && strings.HasPrefix(base, ".") {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || path.Ext(base) == ".go" || strings.HasPrefix(strings.ToUpper(base), "README") {
+			return nil
+		}
+		names = append(names, name)
+		return nil
+	})
+	return names, err
+}
+
+// TrainFS trains a Tagger on every corpus file below dir in fsys, e.g. a
+// directory on disk with os.DirFS. The files are in the word/tag format of
+// the Brown corpus, the options are the same as for New.
+func TrainFS(fsys fs.FS, dir string, options ...Option) (*Tagger, error) {
+	names, err := CorpusFiles(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return Train(NewFSSentenceReader(fsys, names...), options...)
+}
+
+// LoadFS reads a model written by Save from fsys, so a pre-trained model can
+// be embedded in a binary next to, or instead of, the corpus
+func LoadFS(fsys fs.FS, name string) (*Tagger, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
@@ -0,0 +1,33 @@
+package tagger
+
+import (
+	"bytes"
+	_ "embed"
+	"sync"
+)
+
+//go:generate go run ../../cmd/tagger-train -out brown.model
+
+// The bigram HMM trained by cmd/tagger-train on the embedded Brown Corpus
+//
+//go:embed brown.model
+var brownModel []byte
+
+var (
+	brownOnce   sync.Once
+	brownTagger *Tagger
+)
+
+// Brown returns the bigram Tagger trained on the embedded Brown Corpus, the
+// same model New("") trains, without training it. It is read the first time
+// it is needed and shared after that.
+func Brown() *Tagger {
+	brownOnce.Do(func() {
+		t, err := Load(bytes.NewReader(brownModel))
+		if err != nil {
+			panic("tagger: the embedded Brown model is broken: " + err.Error())
+		}
+		brownTagger = t
+	})
+	return brownTagger
+}
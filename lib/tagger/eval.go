@@ -0,0 +1,261 @@
+package tagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// SequenceTagger is anything that can tag a sentence that is already split
+// into words, returning one tag per word
+type SequenceTagger interface {
+	TagWords(words []string) []string
+}
+
+// Evaluation holds the result of tagging a held-out corpus and comparing the
+// tags to the gold ones
+type Evaluation struct {
+	Tokens         int
+	Correct        int
+	KnownTokens    int
+	KnownCorrect   int
+	UnknownTokens  int
+	UnknownCorrect int
+	// Confusion[gold][predicted] counts how often the gold tag was tagged as
+	// predicted, the diagonal holds the correct tags
+	Confusion map[string]map[string]int
+}
+
+// TagScore is the precision, recall and F1 of a single tag. Support is the
+// number of times the tag appears in the gold corpus.
+type TagScore struct {
+	Tag       string  `json:"tag"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+// Evaluate tags every sentence of the gold corpus with the tagger and compares
+// the result to the gold tags. Known and unknown words are only told apart
+// when the tagger has a Known(word string) bool method, like *Tagger does.
+func Evaluate(tagger SequenceTagger, gold SentenceReader) (*Evaluation, error) {
+	known, splitKnown := tagger.(interface {
+		Known(word string) bool
+	})
+
+	eval := NewEvaluation()
+	for {
+		sentence, err := gold.Next()
+		if err == io.EOF {
+			return eval, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		words := make([]string, len(sentence))
+		for i := range sentence {
+			words[i] = sentence[i].Word
+		}
+		predicted := tagger.TagWords(words)
+		for i := range sentence {
+			isKnown := !splitKnown || known.Known(sentence[i].Word)
+			eval.Add(sentence[i].Tag, predicted[i], isKnown)
+		}
+	}
+}
+
+// NewEvaluation returns an empty Evaluation to Add results to
+func NewEvaluation() *Evaluation {
+	return &Evaluation{Confusion: make(map[string]map[string]int)}
+}
+
+// Add counts one tagged word, known tells whether the word was in the
+// training data
+func (e *Evaluation) Add(goldTag string, predictedTag string, known bool) {
+	correct := goldTag == predictedTag
+	e.Tokens++
+	if known {
+		e.KnownTokens++
+	} else {
+		e.UnknownTokens++
+	}
+	if correct {
+		e.Correct++
+		if known {
+			e.KnownCorrect++
+		} else {
+			e.UnknownCorrect++
+		}
+	}
+	if e.Confusion[goldTag] == nil {
+		e.Confusion[goldTag] = make(map[string]int)
+	}
+	e.Confusion[goldTag][predictedTag]++
+}
+
+// Merge adds every count of other to e
+func (e *Evaluation) Merge(other *Evaluation) {
+	e.Tokens += other.Tokens
+	e.Correct += other.Correct
+	e.KnownTokens += other.KnownTokens
+	e.KnownCorrect += other.KnownCorrect
+	e.UnknownTokens += other.UnknownTokens
+	e.UnknownCorrect += other.UnknownCorrect
+	for goldTag, row := range other.Confusion {
+		if e.Confusion[goldTag] == nil {
+			e.Confusion[goldTag] = make(map[string]int)
+		}
+		for predictedTag, count := range row {
+			e.Confusion[goldTag][predictedTag] += count
+		}
+	}
+}
+
+// Accuracy is the fraction of words that were tagged correctly
+func (e *Evaluation) Accuracy() float64 {
+	return ratio(e.Correct, e.Tokens)
+}
+
+// KnownAccuracy is the accuracy over words seen while training
+func (e *Evaluation) KnownAccuracy() float64 {
+	return ratio(e.KnownCorrect, e.KnownTokens)
+}
+
+// UnknownAccuracy is the accuracy over words never seen while training
+func (e *Evaluation) UnknownAccuracy() float64 {
+	return ratio(e.UnknownCorrect, e.UnknownTokens)
+}
+
+func ratio(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+// TagScores returns the precision, recall and F1 of every tag that was in
+// the gold corpus or was predicted, sorted by tag
+func (e *Evaluation) TagScores() []TagScore {
+	gold := make(map[string]int)
+	predicted := make(map[string]int)
+	for goldTag, row := range e.Confusion {
+		for predictedTag, count := range row {
+			gold[goldTag] += count
+			predicted[predictedTag] += count
+		}
+	}
+	tags := make([]string, 0, len(gold))
+	for tag := range gold {
+		tags = append(tags, tag)
+	}
+	for tag := range predicted {
+		if _, ok := gold[tag]; !ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	scores := make([]TagScore, len(tags))
+	for i, tag := range tags {
+		correct := e.Confusion[tag][tag]
+		score := TagScore{
+			Tag:       tag,
+			Precision: ratio(correct, predicted[tag]),
+			Recall:    ratio(correct, gold[tag]),
+			Support:   gold[tag],
+		}
+		if score.Precision+score.Recall > 0 {
+			score.F1 = 2 * score.Precision * score.Recall / (score.Precision + score.Recall)
+		}
+		scores[i] = score
+	}
+	return scores
+}
+
+// Confusion is how often one tag was mistaken for another
+type Confusion struct {
+	Gold      string `json:"gold"`
+	Predicted string `json:"predicted"`
+	Count     int    `json:"count"`
+}
+
+// Confusions returns every mistake, the most frequent first
+func (e *Evaluation) Confusions() []Confusion {
+	confusions := make([]Confusion, 0)
+	for goldTag, row := range e.Confusion {
+		for predictedTag, count := range row {
+			if goldTag != predictedTag {
+				confusions = append(confusions, Confusion{goldTag, predictedTag, count})
+			}
+		}
+	}
+	sort.Slice(confusions, func(i, j int) bool {
+		if confusions[i].Count != confusions[j].Count {
+			return confusions[i].Count > confusions[j].Count
+		}
+		if confusions[i].Gold != confusions[j].Gold {
+			return confusions[i].Gold < confusions[j].Gold
+		}
+		return confusions[i].Predicted < confusions[j].Predicted
+	})
+	return confusions
+}
+
+// WriteText writes a human readable report: the accuracies, a table of the
+// per tag scores and the maxConfusions most frequent mistakes
+func (e *Evaluation) WriteText(w io.Writer, maxConfusions int) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "tokens\t%d\t\n", e.Tokens)
+	fmt.Fprintf(tw, "accuracy\t%.4f\t\n", e.Accuracy())
+	fmt.Fprintf(tw, "known accuracy\t%.4f\t(%d tokens)\t\n", e.KnownAccuracy(), e.KnownTokens)
+	fmt.Fprintf(tw, "unknown accuracy\t%.4f\t(%d tokens)\t\n", e.UnknownAccuracy(), e.UnknownTokens)
+	fmt.Fprintln(tw, "\t\t")
+	fmt.Fprintln(tw, "tag\tprecision\trecall\tf1\tsupport\t")
+	for _, score := range e.TagScores() {
+		fmt.Fprintf(tw, "%s\t%.4f\t%.4f\t%.4f\t%d\t\n", score.Tag, score.Precision, score.Recall, score.F1, score.Support)
+	}
+
+	confusions := e.Confusions()
+	if len(confusions) > maxConfusions {
+		confusions = confusions[:maxConfusions]
+	}
+	if len(confusions) > 0 {
+		fmt.Fprintln(tw, "\t\t")
+		fmt.Fprintln(tw, "gold\tpredicted\tcount\t")
+		for _, confusion := range confusions {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t\n", confusion.Gold, confusion.Predicted, confusion.Count)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes every figure of the evaluation, including the full
+// confusion matrix, as JSON
+func (e *Evaluation) WriteJSON(w io.Writer) error {
+	report := struct {
+		Tokens          int                       `json:"tokens"`
+		Accuracy        float64                   `json:"accuracy"`
+		KnownTokens     int                       `json:"known_tokens"`
+		KnownAccuracy   float64                   `json:"known_accuracy"`
+		UnknownTokens   int                       `json:"unknown_tokens"`
+		UnknownAccuracy float64                   `json:"unknown_accuracy"`
+		Tags            []TagScore                `json:"tags"`
+		Confusion       map[string]map[string]int `json:"confusion"`
+	}{
+		Tokens:          e.Tokens,
+		Accuracy:        e.Accuracy(),
+		KnownTokens:     e.KnownTokens,
+		KnownAccuracy:   e.KnownAccuracy(),
+		UnknownTokens:   e.UnknownTokens,
+		UnknownAccuracy: e.UnknownAccuracy(),
+		Tags:            e.TagScores(),
+		Confusion:       e.Confusion,
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
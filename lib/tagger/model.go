@@ -0,0 +1,482 @@
+package tagger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// The first bytes of every model file
+const modelMagic = "NLTBHMM"
+
+// ModelVersion is the version of the binary model format written by Save,
+// Load refuses any other version.
+const ModelVersion = 2
+
+var (
+	// ErrBadModel is returned by Load when the data is not a tagger model
+	ErrBadModel = errors.New("Not a tagger model")
+	// ErrModelVersion is returned by Load for models of an unsupported version
+	ErrModelVersion = errors.New("Unsupported tagger model version")
+)
+
+// Save writes the trained model in a compact binary format that Load reads
+// back. Maps are written in sorted order so the same model always produces
+// the same bytes.
+func (t *Tagger) Save(w io.Writer) error {
+	mw := &modelWriter{w: bufio.NewWriter(w)}
+	mw.bytes([]byte(modelMagic))
+	mw.uvarint(ModelVersion)
+	mw.uvarint(uint64(t.order))
+
+	numOfTags := t.tagSet.Len()
+	mw.uvarint(uint64(numOfTags))
+	for _, tag := range t.tagSet.tags {
+		mw.string(tag)
+	}
+
+	words := make([]string, 0, len(t.dictionary))
+	for word := range t.dictionary {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	mw.uvarint(uint64(len(words)))
+	for _, word := range words {
+		mw.string(word)
+		mw.uvarint(uint64(len(t.dictionary[word])))
+		for _, tagObject := range t.dictionary[word] {
+			tagIndex, _ := t.tagSet.Index(tagObject.tag)
+			mw.uvarint(uint64(tagIndex))
+			mw.float32(tagObject.freq)
+		}
+	}
+	mw.matrix(t.transMatrix, numOfTags)
+
+	if t.order == Trigram {
+		for _, lambda := range t.lambdas {
+			mw.float32(lambda)
+		}
+		mw.sparseRow(t.tagProb)
+		mw.matrix(t.bigramProb, numOfTags)
+
+		trigrams := make([][3]int, 0, len(t.trigramProb))
+		for trigram := range t.trigramProb {
+			trigrams = append(trigrams, trigram)
+		}
+		sort.Slice(trigrams, func(i, j int) bool {
+			for k := range trigrams[i] {
+				if trigrams[i][k] != trigrams[j][k] {
+					return trigrams[i][k] < trigrams[j][k]
+				}
+			}
+			return false
+		})
+		mw.uvarint(uint64(len(trigrams)))
+		for _, trigram := range trigrams {
+			for _, tagIndex := range trigram {
+				mw.uvarint(uint64(tagIndex))
+			}
+			mw.float32(t.trigramProb[trigram])
+		}
+	}
+
+	mw.suffixModel(t.suffixes)
+
+	if mw.err != nil {
+		return mw.err
+	}
+	return mw.w.Flush()
+}
+
+// SaveFile writes the model to the file at path, see Save
+func (t *Tagger) SaveFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := t.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads a model written by Save
+func Load(r io.Reader) (*Tagger, error) {
+	mr := &modelReader{r: bufio.NewReader(r)}
+	magic := make([]byte, len(modelMagic))
+	if _, err := io.ReadFull(mr.r, magic); err != nil || string(magic) != modelMagic {
+		return nil, ErrBadModel
+	}
+	version := mr.uvarint()
+	if mr.err == nil && version != ModelVersion {
+		return nil, ErrModelVersion
+	}
+
+	t := &Tagger{order: Order(mr.uvarint())}
+	if t.order != Bigram && t.order != Trigram {
+		return nil, ErrBadModel
+	}
+
+	numOfTags := mr.length()
+	if numOfTags > maxModelTags {
+		return nil, ErrBadModel
+	}
+	t.tagSet = &TagSet{index: make(map[string]int, numOfTags)}
+	for i := 0; i < numOfTags && mr.err == nil; i++ {
+		t.tagSet.add(mr.string())
+	}
+	// the matrices below are numOfTags x numOfTags, so a broken header must
+	// stop here
+	if mr.err != nil {
+		return nil, mr.loadError()
+	}
+	if t.tagSet.Len() != numOfTags {
+		return nil, ErrBadModel // the same tag twice
+	}
+
+	numOfWords := mr.length()
+	t.dictionary = make(map[string][]TagFrequency, numOfWords)
+	for i := 0; i < numOfWords && mr.err == nil; i++ {
+		word := mr.string()
+		tagFreqs := make([]TagFrequency, mr.length())
+		for j := range tagFreqs {
+			tagFreqs[j].tag = mr.tag(t.tagSet)
+			tagFreqs[j].freq = mr.float32()
+		}
+		t.dictionary[word] = tagFreqs
+	}
+	t.transMatrix = mr.matrix(numOfTags)
+	if mr.err == nil {
+		t.setLogTrans()
+	}
+
+	if t.order == Trigram {
+		for i := range t.lambdas {
+			t.lambdas[i] = mr.float32()
+		}
+		t.tagProb = mr.sparseRow(numOfTags)
+		t.bigramProb = mr.matrix(numOfTags)
+
+		numOfTrigrams := mr.length()
+		t.trigramProb = make(map[[3]int]float32, numOfTrigrams)
+		for i := 0; i < numOfTrigrams && mr.err == nil; i++ {
+			var trigram [3]int
+			for k := range trigram {
+				trigram[k] = mr.tagIndex(numOfTags)
+			}
+			t.trigramProb[trigram] = mr.float32()
+		}
+	}
+
+	t.suffixes = mr.suffixModel(numOfTags)
+
+	if mr.err != nil {
+		return nil, mr.loadError()
+	}
+	return t, nil
+}
+
+// LoadFile reads the model in the file at path, see Load
+func LoadFile(path string) (*Tagger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// the shape of the JSON export, tags are written as strings everywhere so it
+// can be read without the tag set at hand
+type jsonModel struct {
+	Version     int                           `json:"version"`
+	Order       Order                         `json:"order"`
+	Tags        []string                      `json:"tags"`
+	Dictionary  map[string]map[string]float32 `json:"dictionary"`
+	Transitions map[string]map[string]float32 `json:"transitions"`
+	Lambdas     *[3]float32                   `json:"lambdas,omitempty"`
+	TagProb     map[string]float32            `json:"tag_prob,omitempty"`
+	Trigrams    map[string]float32            `json:"trigrams,omitempty"`
+}
+
+// WriteJSON writes the model as indented JSON for debugging. It can not be
+// loaded back, use Save for that.
+func (t *Tagger) WriteJSON(w io.Writer) error {
+	model := jsonModel{
+		Version:     ModelVersion,
+		Order:       t.order,
+		Tags:        t.tagSet.Tags(),
+		Dictionary:  make(map[string]map[string]float32, len(t.dictionary)),
+		Transitions: make(map[string]map[string]float32),
+	}
+	for word, tagFreqs := range t.dictionary {
+		model.Dictionary[word] = make(map[string]float32, len(tagFreqs))
+		for _, tagObject := range tagFreqs {
+			model.Dictionary[word][tagObject.tag] = tagObject.freq
+		}
+	}
+	for prev, row := range t.transMatrix {
+		model.Transitions[t.tagSet.Tag(prev)] = make(map[string]float32, len(row))
+		for curr, prob := range row {
+			model.Transitions[t.tagSet.Tag(prev)][t.tagSet.Tag(curr)] = prob
+		}
+	}
+	if t.order == Trigram {
+		model.Lambdas = &t.lambdas
+		model.TagProb = make(map[string]float32, len(t.tagProb))
+		for tagIndex, prob := range t.tagProb {
+			model.TagProb[t.tagSet.Tag(tagIndex)] = prob
+		}
+		model.Trigrams = make(map[string]float32, len(t.trigramProb))
+		for trigram, prob := range t.trigramProb {
+			key := t.tagSet.Tag(trigram[0]) + " " + t.tagSet.Tag(trigram[1]) + " " + t.tagSet.Tag(trigram[2])
+			model.Trigrams[key] = prob
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(model)
+}
+
+// Writes the primitives of the model format, the first error sticks and
+// every later write is skipped
+type modelWriter struct {
+	w   *bufio.Writer
+	err error
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (mw *modelWriter) bytes(b []byte) {
+	if mw.err == nil {
+		_, mw.err = mw.w.Write(b)
+	}
+}
+
+func (mw *modelWriter) uvarint(v uint64) {
+	mw.bytes(mw.buf[:binary.PutUvarint(mw.buf[:], v)])
+}
+
+func (mw *modelWriter) string(s string) {
+	mw.uvarint(uint64(len(s)))
+	mw.bytes([]byte(s))
+}
+
+func (mw *modelWriter) float32(f float32) {
+	binary.LittleEndian.PutUint32(mw.buf[:4], math.Float32bits(f))
+	mw.bytes(mw.buf[:4])
+}
+
+// Most entries of a row are the same, zero or the smoothed probability of an
+// unseen transition, so only the common value and the exceptions are written
+func (mw *modelWriter) sparseRow(row []float32) {
+	common := float32(0)
+	if len(row) > 0 {
+		common = row[0]
+		for _, value := range row {
+			if value < common {
+				common = value
+			}
+		}
+	}
+	exceptions := 0
+	for _, value := range row {
+		if value != common {
+			exceptions++
+		}
+	}
+	mw.float32(common)
+	mw.uvarint(uint64(exceptions))
+	for col, value := range row {
+		if value != common {
+			mw.uvarint(uint64(col))
+			mw.float32(value)
+		}
+	}
+}
+
+func (mw *modelWriter) matrix(matrix [][]float32, size int) {
+	for row := 0; row < size; row++ {
+		mw.sparseRow(matrix[row])
+	}
+}
+
+// A missing suffix model is written as zero tries
+func (mw *modelWriter) suffixModel(model *suffixModel) {
+	if model == nil {
+		mw.uvarint(0)
+		return
+	}
+	mw.uvarint(numOfSuffixClasses)
+	for _, trie := range model.tries {
+		mw.suffixNode(trie.root)
+	}
+}
+
+func (mw *modelWriter) suffixNode(node *suffixNode) {
+	tags := make([]int, 0, len(node.counts))
+	for tagIndex := range node.counts {
+		tags = append(tags, tagIndex)
+	}
+	sort.Ints(tags)
+	mw.uvarint(uint64(len(tags)))
+	for _, tagIndex := range tags {
+		mw.uvarint(uint64(tagIndex))
+		mw.uvarint(uint64(node.counts[tagIndex]))
+	}
+	letters := node.sortedChildren()
+	mw.uvarint(uint64(len(letters)))
+	for _, letter := range letters {
+		mw.uvarint(uint64(letter))
+		mw.suffixNode(node.children[letter])
+	}
+}
+
+// The reading side of modelWriter, also with a sticky error. Lengths and tag
+// indexes are checked so a corrupt file can not cause huge allocations or
+// out of range panics.
+type modelReader struct {
+	r   *bufio.Reader
+	err error
+	buf [4]byte
+}
+
+// nothing in a model comes close to this many entries
+const maxModelLength = 1 << 26
+
+// the Brown Corpus has 472 tags, the transition matrices of this many tags
+// still fit in memory
+const maxModelTags = 4096
+
+func (mr *modelReader) uvarint() uint64 {
+	if mr.err != nil {
+		return 0
+	}
+	var v uint64
+	v, mr.err = binary.ReadUvarint(mr.r)
+	return v
+}
+
+func (mr *modelReader) length() int {
+	v := mr.uvarint()
+	if v > maxModelLength {
+		mr.fail()
+		return 0
+	}
+	return int(v)
+}
+
+func (mr *modelReader) fail() {
+	if mr.err == nil {
+		mr.err = ErrBadModel
+	}
+}
+
+// the error for Load to return, a file that ends too early is a bad model
+func (mr *modelReader) loadError() error {
+	if mr.err == io.EOF || mr.err == io.ErrUnexpectedEOF {
+		return ErrBadModel
+	}
+	return mr.err
+}
+
+func (mr *modelReader) string() string {
+	n := mr.length()
+	if mr.err != nil {
+		return ""
+	}
+	b := make([]byte, n)
+	_, mr.err = io.ReadFull(mr.r, b)
+	return string(b)
+}
+
+func (mr *modelReader) float32() float32 {
+	if mr.err != nil {
+		return 0
+	}
+	if _, mr.err = io.ReadFull(mr.r, mr.buf[:]); mr.err != nil {
+		return 0
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(mr.buf[:]))
+}
+
+func (mr *modelReader) tagIndex(numOfTags int) int {
+	i := mr.length()
+	if i >= numOfTags {
+		mr.fail()
+		return 0
+	}
+	return i
+}
+
+func (mr *modelReader) tag(tagSet *TagSet) string {
+	i := mr.tagIndex(tagSet.Len())
+	if mr.err != nil {
+		return ""
+	}
+	return tagSet.Tag(i)
+}
+
+func (mr *modelReader) sparseRow(size int) []float32 {
+	row := make([]float32, size)
+	common := mr.float32()
+	for col := range row {
+		row[col] = common
+	}
+	exceptions := mr.length()
+	for i := 0; i < exceptions && mr.err == nil; i++ {
+		col := mr.tagIndex(size)
+		row[col] = mr.float32()
+	}
+	return row
+}
+
+func (mr *modelReader) matrix(size int) [][]float32 {
+	matrix := make([][]float32, size)
+	for row := 0; row < size && mr.err == nil; row++ {
+		matrix[row] = mr.sparseRow(size)
+	}
+	return matrix
+}
+
+func (mr *modelReader) suffixModel(numOfTags int) *suffixModel {
+	switch mr.length() {
+	case 0:
+		return nil
+	case numOfSuffixClasses:
+	default:
+		mr.fail()
+		return nil
+	}
+	model := &suffixModel{}
+	for class := range model.tries {
+		model.tries[class] = &suffixTrie{root: mr.suffixNode(numOfTags, 0)}
+	}
+	model.finish(numOfTags)
+	return model
+}
+
+// depth is checked so a corrupt file can not recurse without end
+func (mr *modelReader) suffixNode(numOfTags int, depth int) *suffixNode {
+	node := newSuffixNode()
+	if depth > maxSuffixLength {
+		mr.fail()
+		return node
+	}
+	numOfCounts := mr.length()
+	for i := 0; i < numOfCounts && mr.err == nil; i++ {
+		node.add(mr.tagIndex(numOfTags), mr.length())
+	}
+	numOfChildren := mr.length()
+	for i := 0; i < numOfChildren && mr.err == nil; i++ {
+		letter := rune(mr.length())
+		node.children[letter] = mr.suffixNode(numOfTags, depth+1)
+	}
+	return node
+}
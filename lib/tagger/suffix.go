@@ -0,0 +1,192 @@
+package tagger
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The longest suffix the unknown word model looks at
+const maxSuffixLength = 10
+
+// Words seen at most this often are rare, the unknown word model is learned
+// from them as they are the most like words that were never seen
+const rareWordCount = 10
+
+// A class with fewer rare words than this borrows the model of the plain
+// class with the same capitalisation
+const minSuffixClassCount = 50
+
+// Unknown words are split into classes by capitalisation and by whether
+// they have digits or hyphens, every class has its own suffix trie
+const (
+	classPlain = iota
+	classDigit
+	classHyphen
+	numOfFeatures
+
+	numOfSuffixClasses = 2 * numOfFeatures
+)
+
+// The unknown word model of TnT (Brants 2000). It estimates the probability
+// of every tag given the last letters of a word from the rare words of the
+// training corpus, backing off from long suffixes to shorter ones.
+type suffixModel struct {
+	tries [numOfSuffixClasses]*suffixTrie
+}
+
+// A trie of the suffixes of rare words, read from the last letter backwards.
+// Every node counts the tags of the words ending with the letters on its
+// path, the root counts every rare word.
+type suffixTrie struct {
+	root *suffixNode
+	// the weight of the shorter suffix when backing off, the standard
+	// deviation of the tag probabilities at the root
+	theta float64
+}
+
+type suffixNode struct {
+	counts   map[int]int
+	total    int
+	children map[rune]*suffixNode
+}
+
+func newSuffixModel() *suffixModel {
+	model := &suffixModel{}
+	for class := range model.tries {
+		model.tries[class] = &suffixTrie{root: newSuffixNode()}
+	}
+	return model
+}
+
+func newSuffixNode() *suffixNode {
+	return &suffixNode{counts: make(map[int]int), children: make(map[rune]*suffixNode)}
+}
+
+// The class of a word, capitalised words have the odd classes
+func suffixClass(word string) int {
+	class := classPlain
+	switch {
+	case strings.IndexFunc(word, unicode.IsDigit) >= 0:
+		class = classDigit
+	case strings.ContainsRune(word, '-'):
+		class = classHyphen
+	}
+	if r, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(r) {
+		return 2*class + 1
+	}
+	return 2 * class
+}
+
+// Counts count occurrences of word with the tag at every suffix of the word
+func (model *suffixModel) add(word string, tagIndex int, count int) {
+	node := model.tries[suffixClass(word)].root
+	node.add(tagIndex, count)
+	runes := []rune(strings.ToLower(word))
+	for i := len(runes) - 1; i >= 0 && i >= len(runes)-maxSuffixLength; i-- {
+		child := node.children[runes[i]]
+		if child == nil {
+			child = newSuffixNode()
+			node.children[runes[i]] = child
+		}
+		child.add(tagIndex, count)
+		node = child
+	}
+}
+
+func (node *suffixNode) add(tagIndex int, count int) {
+	node.counts[tagIndex] += count
+	node.total += count
+}
+
+// Works out theta of every trie, done once all words are added
+func (model *suffixModel) finish(numOfTags int) {
+	for _, trie := range model.tries {
+		trie.theta = 0
+		if trie.root.total == 0 || numOfTags < 2 {
+			continue
+		}
+		mean := 1 / float64(numOfTags)
+		var sum float64
+		for tagIndex := 0; tagIndex < numOfTags; tagIndex++ {
+			diff := float64(trie.root.counts[tagIndex])/float64(trie.root.total) - mean
+			sum += diff * diff
+		}
+		trie.theta = math.Sqrt(sum / float64(numOfTags-1))
+	}
+}
+
+// Returns the probability of every tag given the suffixes of the word, or
+// nil when the model has not seen any words like it
+func (model *suffixModel) probs(word string, numOfTags int) []float64 {
+	class := suffixClass(word)
+	trie := model.tries[class]
+	if trie.root.total < minSuffixClassCount {
+		trie = model.tries[class%2]
+	}
+	if trie.root.total == 0 {
+		return nil
+	}
+
+	probs := make([]float64, numOfTags)
+	trie.root.addProbs(probs, 1)
+	node := trie.root
+	runes := []rune(strings.ToLower(word))
+	for i := len(runes) - 1; i >= 0 && i >= len(runes)-maxSuffixLength; i-- {
+		node = node.children[runes[i]]
+		if node == nil {
+			break
+		}
+		// P(t|suffix) = (count(t,suffix)/count(suffix) + theta P(t|shorter suffix)) / (1 + theta)
+		weight := 1 / (1 + trie.theta)
+		for tagIndex := range probs {
+			probs[tagIndex] *= trie.theta * weight
+		}
+		node.addProbs(probs, weight)
+	}
+	return probs
+}
+
+// Adds the relative frequency of every tag at the node, times weight
+func (node *suffixNode) addProbs(probs []float64, weight float64) {
+	for tagIndex, count := range node.counts {
+		if tagIndex < len(probs) {
+			probs[tagIndex] += weight * float64(count) / float64(node.total)
+		}
+	}
+}
+
+// The children of a node in a fixed order, so saved models are the same
+// byte for byte
+func (node *suffixNode) sortedChildren() []rune {
+	letters := make([]rune, 0, len(node.children))
+	for letter := range node.children {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	return letters
+}
+
+// Learns the unknown word model from the words of the dictionary seen at
+// most rareWordCount times. The dictionary must still hold counts.
+func learnSuffixes(dictionary map[string][]TagFrequency, tagSet *TagSet) *suffixModel {
+	model := newSuffixModel()
+	for word, tagFreqs := range dictionary {
+		var count float32
+		for _, tagObject := range tagFreqs {
+			count += tagObject.freq
+		}
+		if count > rareWordCount {
+			continue
+		}
+		for _, tagObject := range tagFreqs {
+			if tagIndex, ok := tagSet.Index(tagObject.tag); ok {
+				model.add(word, tagIndex, int(tagObject.freq))
+			}
+		}
+	}
+	model.finish(tagSet.Len())
+	return model
+}
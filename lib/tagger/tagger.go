@@ -42,6 +42,12 @@ import (
 // representing that word in the sentence and the part of speech for
 // that word
 func (copyrightTagger *Tagger) TagBytes(rawBytes []byte) []TaggedWord {
+	return TagBytes(copyrightTagger, rawBytes)
+}
+
+// TagBytes splits the raw bytes into words the same way Tagger.TagBytes does
+// and tags them with any SequenceTagger
+func TagBytes(sequenceTagger SequenceTagger, rawBytes []byte) []TaggedWord {
 	// ERROR AND SANITIZATION CHECKS
 	var wrdArry = make([]TaggedWord, 0)
 	if len(rawBytes) < 1 { // do I even need to do any work
@@ -54,72 +60,13 @@ func (copyrightTagger *Tagger) TagBytes(rawBytes []byte) []TaggedWord {
 	// split the sentence propperly
 	wrdArry = mkWrdArray(rawBytes)
 
-	sentLength := len(wrdArry) + 1             // I need 1 more for the start of the sentence
-	sentMatrix := make([][]float32, numOfTags) // Create the sentence Matrix
-	for row := range sentMatrix {
-		sentMatrix[row] = make([]float32, sentLength)
-	}
-
-	// initialize the first column
-	var lastBestProb float32 = 1.0
-	var lastBestTag = TagStrToInt["."]
-	var currBestProb float32 = 0.0
-	var currBestTag int = 0
-
-	sentMatrix[TagStrToInt["."]][0] = 1.0 // the max probability something can be
-	for wrdIndex := 0; wrdIndex < len(wrdArry); wrdIndex++ {
-		for tagIndex := 0; tagIndex < numOfTags; tagIndex++ {
-			var lowerCaseWord = strings.ToLower(wrdArry[wrdIndex].Word)
-
-			var currTrans float32 = copyrightTagger.TransMatrix[lastBestTag][tagIndex]
-			var currProb float32 = lastBestProb * currTrans
-
-			if len(copyrightTagger.Dictionary[wrdArry[wrdIndex].Word]) != 0 { // has the word been seen before?
-
-				if wrdArry[wrdIndex].Word == "." || wrdArry[wrdIndex].Word == "?" || wrdArry[wrdIndex].Word == "!" {
-					sentMatrix[TagStrToInt["."]][wrdIndex+1] = 1.0
-				} else {
-					for _, tagObject := range copyrightTagger.Dictionary[wrdArry[wrdIndex].Word] {
-						if TagIntToStr[tagIndex] == tagObject.tag {
-							sentMatrix[tagIndex][wrdIndex+1] = currProb * tagObject.freq
-						}
-					}
-				}
-				// check for the word not caring about capitalization
-			} else if len(copyrightTagger.Dictionary[lowerCaseWord]) != 0 {
-				for _, tagObject := range copyrightTagger.Dictionary[lowerCaseWord] {
-					if TagIntToStr[tagIndex] == tagObject.tag {
-						sentMatrix[tagIndex][wrdIndex+1] = currProb * tagObject.freq
-					}
-				}
-			} else { // Try to determine tag based on transitional probability and word itself
-				if currTrans >= 0.7 {
-					sentMatrix[tagIndex][wrdIndex+1] = currProb
-				} else {
-					likelyTag := tagUnkown(wrdArry[wrdIndex].Word)
-					sentMatrix[TagStrToInt[likelyTag]][wrdIndex+1] = currProb * 0.95
-				}
-			}
-			// see if this is the best transition for next column
-			if currProb > currBestProb {
-				currBestProb = currProb
-				currBestTag = tagIndex
-			}
-		}
-		lastBestProb = currBestProb
-		lastBestTag = currBestTag
+	// find the most likely tag sequence for the whole sentence
+	words := make([]string, len(wrdArry))
+	for wrdIndex := range wrdArry {
+		words[wrdIndex] = wrdArry[wrdIndex].Word
 	}
-	// Sentence Matrix Created.
-	// Now walk through the matrix assigning the best tag to each word
-
-	for wrdIndex := 0; wrdIndex < len(wrdArry); wrdIndex++ {
-		var tagProb float32 = 0.0
-		for tagIndex := 0; tagIndex < numOfTags; tagIndex++ {
-			if sentMatrix[tagIndex][wrdIndex+1] > tagProb {
-				tagProb = sentMatrix[tagIndex][wrdIndex+1]
-				wrdArry[wrdIndex].Tag = TagIntToStr[tagIndex]
-			}
-		}
+	for wrdIndex, tag := range sequenceTagger.TagWords(words) {
+		wrdArry[wrdIndex].Tag = tag
 	}
 
 	// compress numbers and propper nouns that might have been split
@@ -129,6 +76,21 @@ func (copyrightTagger *Tagger) TagBytes(rawBytes []byte) []TaggedWord {
 	return wrdArry
 }
 
+// TagWords returns the most likely tag for every word of a sentence that is
+// already split into words
+func (copyrightTagger *Tagger) TagWords(words []string) []string {
+	if copyrightTagger.order == Trigram {
+		return copyrightTagger.viterbiTrigram(words)
+	}
+	return copyrightTagger.viterbi(words)
+}
+
+// Known reports whether the word was seen while training, either as written
+// or lower cased
+func (copyrightTagger *Tagger) Known(word string) bool {
+	return len(copyrightTagger.dictionary[word]) != 0 || len(copyrightTagger.dictionary[strings.ToLower(word)]) != 0
+}
+
 /*
 // The DFA required for number compression
 // into one number not number period number
@@ -0,0 +1,321 @@
+package tagger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// builds a small hand made model where every transition is possible
+func testTagger() *Tagger {
+	tagSet := newTagSet()
+	for _, tag := range []string{"at", "nn", "nns", "vb", "vbz", "jj", "np", "cd", "rb", "fw"} {
+		tagSet.add(tag)
+	}
+	transMatrix := make([][]float32, 0)
+	growTransMatrix(&transMatrix, tagSet.Len())
+	for row := range transMatrix {
+		for col := range transMatrix[row] {
+			transMatrix[row][col] = 0.001
+		}
+	}
+	tag := func(tag string) int {
+		i, _ := tagSet.Index(tag)
+		return i
+	}
+	transMatrix[tag(".")][tag("at")] = 0.5
+	transMatrix[tag("at")][tag("nn")] = 0.6
+	transMatrix[tag("at")][tag("vb")] = 0.01
+	transMatrix[tag("nn")][tag("vbz")] = 0.4
+	transMatrix[tag("nn")][tag("nn")] = 0.2
+	transMatrix[tag("vbz")][tag(".")] = 0.5
+
+	dictionary := map[string][]TagFrequency{
+		"the":   {{"at", 1}},
+		"dog":   {{"nn", 1}},
+		"walk":  {{"vb", 0.6}, {"nn", 0.4}},
+		"walks": {{"vbz", 0.5}, {"nns", 0.5}},
+		".":     {{".", 1}},
+	}
+	tagger := &Tagger{tagSet: tagSet, dictionary: dictionary, transMatrix: transMatrix}
+	tagger.setLogTrans()
+	return tagger
+}
+
+func TestTagger_viterbi(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{
+			name:  "empty",
+			words: []string{},
+			want:  []string{},
+		},
+		{
+			name:  "simple",
+			words: []string{"The", "dog", "walks", "."},
+			want:  []string{"at", "nn", "vbz", "."},
+		},
+		{
+			// walk is more often a verb, but never after an article
+			name:  "path beats column winner",
+			words: []string{"the", "walk", "."},
+			want:  []string{"at", "nn", "."},
+		},
+	}
+	tagger := testTagger()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagger.viterbi(tt.words); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tagger.viterbi() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagger_viterbiLongSentence(t *testing.T) {
+	words := strings.Fields(strings.Repeat("the dog walks ", 500))
+	got := testTagger().viterbi(words)
+	for i, tag := range got {
+		if tag != []string{"at", "nn", "vbz"}[i%3] {
+			t.Fatalf("Tagger.viterbi() word %d = %v, underflow?", i, tag)
+		}
+	}
+}
+
+func TestTagger_viterbiTrigram(t *testing.T) {
+	tagger := testTagger()
+	tagger.order = Trigram
+	counts := newNgramCounts()
+	prev2, prev1 := 0, 0
+	for i := 0; i < 20; i++ {
+		for _, tag := range []string{"at", "nn", "vbz", "."} {
+			curr, _ := tagger.tagSet.Index(tag)
+			counts.increment(prev2, prev1, curr)
+			prev2, prev1 = prev1, curr
+		}
+	}
+	counts.apply(tagger, tagger.tagSet.Len())
+
+	var total float32
+	for _, lambda := range tagger.lambdas {
+		total += lambda
+	}
+	if total < 0.999 || total > 1.001 {
+		t.Errorf("Tagger.lambdas = %v, want them to sum to 1", tagger.lambdas)
+	}
+
+	want := []string{"at", "nn", "vbz", "at", "nn", "."}
+	if got := tagger.viterbiTrigram([]string{"the", "walk", "walks", "the", "dog", "."}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tagger.viterbiTrigram() = %v, want %v", got, want)
+	}
+}
+
+func TestTagSet(t *testing.T) {
+	tagSet := newTagSet()
+	for _, tag := range []string{"at", "np-tl", "at", "nn"} {
+		tagSet.add(tag)
+	}
+	if got, want := tagSet.Tags(), []string{".", "at", "np-tl", "nn"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TagSet.Tags() = %v, want %v", got, want)
+	}
+	if i, ok := tagSet.Index("np-tl"); !ok || tagSet.Tag(i) != "np-tl" {
+		t.Errorf("TagSet.Index(np-tl) = %v, %v", i, ok)
+	}
+	if tagSet.Contains("bos") {
+		t.Errorf("TagSet.Contains(bos) = true, want false")
+	}
+}
+
+func TestSuffixModel(t *testing.T) {
+	corpus := "the/at kindness/nn of/in Smith/np ended/vbd quickly/rb ./.\n" +
+		"the/at darkness/nn of/in Jones/np fell/vbd slowly/rb ./.\n" +
+		"the/at sadness/nn of/in Brown/np passed/vbd gently/rb ./.\n"
+	tagger, err := Train(NewSentenceReader(strings.NewReader(corpus)))
+	if err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"happiness", "nn"},
+		{"softly", "rb"},
+		{"Taylor", "np"},
+	}
+	for _, tt := range tests {
+		probs := tagger.suffixes.probs(tt.word, tagger.tagSet.Len())
+		best := 0
+		for tagIndex := range probs {
+			if probs[tagIndex] > probs[best] {
+				best = tagIndex
+			}
+		}
+		if got := tagger.tagSet.Tag(best); got != tt.want {
+			t.Errorf("suffixModel.probs(%q) is highest for %v, want %v", tt.word, got, tt.want)
+		}
+	}
+	if got := tagger.TagWords([]string{"the", "happiness", "of", "Taylor", "ended", "softly", "."}); !reflect.DeepEqual(got, []string{"at", "nn", "in", "np", "vbd", "rb", "."}) {
+		t.Errorf("Tagger.TagWords() = %v", got)
+	}
+}
+
+func TestTrain(t *testing.T) {
+	corpus := "\tThe/at dog/nn walks/vbz ./.\n\n\tA/at dog/nn ran/vbd 1-1/2/cd miles/nns ./.\n"
+	tagger, err := Train(NewSentenceReader(strings.NewReader(corpus)))
+	if err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+	if got, want := tagger.TagSet().Tags(), []string{".", "at", "nn", "vbz", "vbd", "cd", "nns"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Train() tags = %v, want %v", got, want)
+	}
+	if got := tagger.dictionary["1-1/2"]; len(got) != 1 || got[0].tag != "cd" {
+		t.Errorf("Train() Dictionary[1-1/2] = %v", got)
+	}
+
+	sentences := [][]TaggedWord{{{Word: "the", Tag: "at"}, {Word: "dog", Tag: "nn"}}}
+	if _, err := Train(NewSliceSentenceReader(sentences), WithOrder(Trigram)); err != nil {
+		t.Errorf("Train() trigram error = %v", err)
+	}
+	if _, err := Train(NewSliceSentenceReader(nil)); err != ErrNoTrainingData {
+		t.Errorf("Train() empty error = %v, want %v", err, ErrNoTrainingData)
+	}
+	for _, order := range []Order{0, Order(3)} {
+		if _, err := Train(NewSliceSentenceReader(sentences), WithOrder(order)); err != ErrBadOrder {
+			t.Errorf("Train() order %d error = %v, want %v", order, err, ErrBadOrder)
+		}
+	}
+}
+
+func TestTrainFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ca01":      {Data: []byte("\tThe/at dog/nn walks/vbz ./.\n")},
+		"README":    {Data: []byte("The Brown Corpus, not tagged\n")},
+		".hidden":   {Data: []byte("broken\n")},
+		"sub/ca02":  {Data: []byte("\tA/at dog/nn ran/vbd ./.\n")},
+		"corpus.go": {Data: []byte("package corpus\n")},
+	}
+	tagger, err := TrainFS(fsys, ".")
+	if err != nil {
+		t.Fatalf("TrainFS() error = %v", err)
+	}
+	if got, want := tagger.TagSet().Tags(), []string{".", "at", "nn", "vbz", "vbd"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TrainFS() tags = %v, want %v", got, want)
+	}
+	if _, err := TrainFS(os.DirFS("/nonexistent"), "."); err == nil {
+		t.Errorf("TrainFS() of a missing directory error = nil")
+	}
+	if _, err := New("/nonexistent"); err == nil {
+		t.Errorf("New() of a missing directory error = nil")
+	}
+}
+
+// brown.model has to be made again with go generate when training changes
+func TestBrown(t *testing.T) {
+	trained, err := New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if !reflect.DeepEqual(Brown(), trained) {
+		t.Errorf("Brown() is not the model New(\"\") trains, run go generate")
+	}
+}
+
+func TestTagger_SaveLoad(t *testing.T) {
+	corpus := "The/at dog/nn walks/vbz ./.\nA/at dog/nn ran/vbd 1-1/2/cd miles/nns ./.\n"
+	for _, order := range []Order{Bigram, Trigram} {
+		tagger, err := Train(NewSentenceReader(strings.NewReader(corpus)), WithOrder(order))
+		if err != nil {
+			t.Fatalf("Train() error = %v", err)
+		}
+		var saved bytes.Buffer
+		if err := tagger.Save(&saved); err != nil {
+			t.Fatalf("Tagger.Save() error = %v", err)
+		}
+		loaded, err := Load(bytes.NewReader(saved.Bytes()))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if !reflect.DeepEqual(loaded, tagger) {
+			t.Errorf("Load() order %v = %+v, want %+v", order, loaded, tagger)
+		}
+		if _, err := Load(bytes.NewReader(saved.Bytes()[:saved.Len()/2])); err != ErrBadModel {
+			t.Errorf("Load() truncated error = %v, want %v", err, ErrBadModel)
+		}
+		if _, err := Load(bytes.NewReader(saved.Bytes()[:len(modelMagic)+5])); err != ErrBadModel {
+			t.Errorf("Load() truncated header error = %v, want %v", err, ErrBadModel)
+		}
+		if err := tagger.WriteJSON(ioutil.Discard); err != nil {
+			t.Errorf("Tagger.WriteJSON() error = %v", err)
+		}
+	}
+}
+
+func TestLoad_badHeader(t *testing.T) {
+	// the tag count of the header decides the size of the matrices
+	for _, header := range []string{
+		modelMagic + "\x02\x01\x80\x80\x01",         // 16384 tags
+		modelMagic + "\x02\x01\x80\x80\x80\x20",     // 2^26 tags
+		modelMagic + "\x02\x01\xe8\x07\x02at\x02nn", // 1000 tags, 2 names
+	} {
+		if _, err := Load(strings.NewReader(header)); err != ErrBadModel {
+			t.Errorf("Load(%q) error = %v, want %v", header, err, ErrBadModel)
+		}
+	}
+	for _, header := range []string{modelMagic + "\x01\x01\x02", modelMagic + "\x03\x01\x02"} {
+		if _, err := Load(strings.NewReader(header)); err != ErrModelVersion {
+			t.Errorf("Load(%q) error = %v, want %v", header, err, ErrModelVersion)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	gold := [][]TaggedWord{{
+		{Word: "The", Tag: "at"}, {Word: "walk", Tag: "nn"}, {Word: "walks", Tag: "vbz"}, {Word: "Smith", Tag: "np"}, {Word: ".", Tag: "."},
+	}}
+	eval, err := Evaluate(testTagger(), NewSliceSentenceReader(gold))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if eval.Tokens != 5 || eval.UnknownTokens != 1 {
+		t.Errorf("Evaluate() tokens = %v, unknown = %v", eval.Tokens, eval.UnknownTokens)
+	}
+	if got := eval.Accuracy(); got != float64(eval.Correct)/5 {
+		t.Errorf("Evaluation.Accuracy() = %v", got)
+	}
+	for _, score := range eval.TagScores() {
+		if score.Tag == "at" && (score.Precision != 1 || score.Recall != 1 || score.F1 != 1) {
+			t.Errorf("Evaluation.TagScores() at = %+v", score)
+		}
+	}
+	var text bytes.Buffer
+	if err := eval.WriteText(&text, 10); err != nil || !strings.Contains(text.String(), "accuracy") {
+		t.Errorf("Evaluation.WriteText() = %q, %v", text.String(), err)
+	}
+}
+
+func Test_mkWrdArray(t *testing.T) {
+	input := "Café owners, naïvely.  "
+	want := []TaggedWord{
+		{Word: "Café", ByteStart: 0, ByteEnd: 5, RuneStart: 0, RuneEnd: 4},
+		{Word: "owners", ByteStart: 6, ByteEnd: 12, RuneStart: 5, RuneEnd: 11},
+		{Word: ",", ByteStart: 12, ByteEnd: 13, RuneStart: 11, RuneEnd: 12},
+		{Word: "naïvely", ByteStart: 14, ByteEnd: 22, RuneStart: 13, RuneEnd: 20},
+		{Word: ".", ByteStart: 22, ByteEnd: 23, RuneStart: 20, RuneEnd: 21},
+	}
+	got := mkWrdArray([]byte(input))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mkWrdArray() = %+v, want %+v", got, want)
+	}
+	for _, word := range got {
+		if input[word.ByteStart:word.ByteEnd] != word.Word || string([]rune(input)[word.RuneStart:word.RuneEnd]) != word.Word {
+			t.Errorf("mkWrdArray() offsets of %q do not match the input", word.Word)
+		}
+	}
+}
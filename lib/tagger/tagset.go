@@ -0,0 +1,58 @@
+package tagger
+
+// The tag every sentence is assumed to follow, the Brown corpus tags
+// sentence ending punctuation with it.
+const sentenceEnd = "."
+
+// TagSet is the inventory of part of speech tags a model knows about. It is
+// discovered while reading the training corpus, tags are numbered in the
+// order they are first seen.
+type TagSet struct {
+	tags  []string
+	index map[string]int
+}
+
+// Creates a tag set that only holds the sentence boundary tag, which every
+// model needs as its start state
+func newTagSet() *TagSet {
+	tagSet := &TagSet{index: make(map[string]int)}
+	tagSet.add(sentenceEnd)
+	return tagSet
+}
+
+// Returns the index of the tag, adding it to the set if it is new
+func (tagSet *TagSet) add(tag string) int {
+	if i, ok := tagSet.index[tag]; ok {
+		return i
+	}
+	tagSet.index[tag] = len(tagSet.tags)
+	tagSet.tags = append(tagSet.tags, tag)
+	return len(tagSet.tags) - 1
+}
+
+// Index returns the index of the tag and whether the tag is part of the set
+func (tagSet *TagSet) Index(tag string) (int, bool) {
+	i, ok := tagSet.index[tag]
+	return i, ok
+}
+
+// Tag returns the tag stored at the given index
+func (tagSet *TagSet) Tag(index int) string {
+	return tagSet.tags[index]
+}
+
+// Contains reports whether the tag is part of the set
+func (tagSet *TagSet) Contains(tag string) bool {
+	_, ok := tagSet.index[tag]
+	return ok
+}
+
+// Len returns the number of tags in the set
+func (tagSet *TagSet) Len() int {
+	return len(tagSet.tags)
+}
+
+// Tags returns a copy of every tag in the set, in index order
+func (tagSet *TagSet) Tags() []string {
+	return append([]string(nil), tagSet.tags...)
+}
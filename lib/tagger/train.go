@@ -0,0 +1,159 @@
+package tagger
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// ErrNoTrainingData is returned by Train when the sentences held no tagged words
+var ErrNoTrainingData = errors.New("No tagged words to train on")
+
+// ErrBadOrder is returned by Train when WithOrder was given something other
+// than Bigram or Trigram
+var ErrBadOrder = errors.New("Unsupported model order")
+
+// SentenceReader is a source of tagged sentences to train a Tagger on
+type SentenceReader interface {
+	// Next returns the next sentence, or io.EOF once there are none left
+	Next() ([]TaggedWord, error)
+}
+
+// Train builds a Tagger from every sentence the reader returns. The options
+// are the same as for New.
+func Train(sentences SentenceReader, options ...Option) (*Tagger, error) {
+	train := newTrainer(options...)
+	if order := train.tagger.order; order != Bigram && order != Trigram {
+		return nil, ErrBadOrder
+	}
+	words := 0
+	for {
+		sentence, err := sentences.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		train.addSentence(sentence)
+		words += len(sentence)
+	}
+	if words == 0 {
+		return nil, ErrNoTrainingData
+	}
+	return train.finish(), nil
+}
+
+// ParseTaggedSentence splits one line of a corpus into its words and tags.
+// Every word|~|tag pair is separated by white space and the tag follows the
+// last delimeter, words like 1-1/2 contain it too. Anything without a tag is
+// dropped.
+func ParseTaggedSentence(line string) []TaggedWord {
+	sentence := make([]TaggedWord, 0)
+	for _, word := range strings.Fields(line) {
+		split := strings.LastIndex(word, SPLITCHARS)
+		if split > 0 && split < len(word)-len(SPLITCHARS) {
+			sentence = append(sentence, TaggedWord{Word: word[:split], Tag: word[split+len(SPLITCHARS):]})
+		}
+	}
+	return sentence
+}
+
+// reads one sentence per line in the word/tag format of the Brown corpus
+type lineSentenceReader struct {
+	reader *bufio.Reader
+}
+
+// NewSentenceReader reads sentences in the word/tag format of the Brown
+// corpus, one sentence per line. Blank lines are skipped.
+func NewSentenceReader(r io.Reader) SentenceReader {
+	return &lineSentenceReader{reader: bufio.NewReader(r)}
+}
+
+func (r *lineSentenceReader) Next() ([]TaggedWord, error) {
+	for {
+		line, err := r.reader.ReadString('\n')
+		if len(line) > 0 {
+			if sentence := ParseTaggedSentence(line); len(sentence) > 0 {
+				return sentence, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reads the files one after the other, only one is open at a time
+type fileSentenceReader struct {
+	paths   []string
+	open    func(name string) (io.ReadCloser, error)
+	file    io.ReadCloser
+	current SentenceReader
+}
+
+// NewFileSentenceReader reads the sentences of every file in turn, the
+// files are in the same format NewSentenceReader expects
+func NewFileSentenceReader(paths ...string) SentenceReader {
+	return &fileSentenceReader{paths: paths, open: func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	}}
+}
+
+// NewFSSentenceReader is NewFileSentenceReader for files in fsys
+func NewFSSentenceReader(fsys fs.FS, names ...string) SentenceReader {
+	return &fileSentenceReader{paths: names, open: func(name string) (io.ReadCloser, error) {
+		return fsys.Open(name)
+	}}
+}
+
+func (r *fileSentenceReader) Next() ([]TaggedWord, error) {
+	for {
+		if r.current == nil {
+			if len(r.paths) == 0 {
+				return nil, io.EOF
+			}
+			file, err := r.open(r.paths[0])
+			if err != nil {
+				return nil, err
+			}
+			r.paths = r.paths[1:]
+			r.file = file
+			r.current = NewSentenceReader(file)
+		}
+
+		sentence, err := r.current.Next()
+		if err == io.EOF {
+			r.file.Close()
+			r.current = nil
+			continue
+		}
+		if err != nil {
+			r.file.Close()
+			return nil, err
+		}
+		return sentence, nil
+	}
+}
+
+// returns sentences that are already in memory
+type sliceSentenceReader struct {
+	sentences [][]TaggedWord
+}
+
+// NewSliceSentenceReader returns the given sentences one at a time
+func NewSliceSentenceReader(sentences [][]TaggedWord) SentenceReader {
+	return &sliceSentenceReader{sentences: sentences}
+}
+
+func (r *sliceSentenceReader) Next() ([]TaggedWord, error) {
+	if len(r.sentences) == 0 {
+		return nil, io.EOF
+	}
+	sentence := r.sentences[0]
+	r.sentences = r.sentences[1:]
+	return sentence, nil
+}
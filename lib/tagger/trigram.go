@@ -0,0 +1,231 @@
+package tagger
+
+import (
+	"math"
+)
+
+// Order is the number of previous tags a transition is conditioned on
+type Order int
+
+const (
+	// Bigram is a first order HMM: P(tag | previous tag)
+	Bigram Order = iota + 1
+	// Trigram is a second order HMM: P(tag | two previous tags) smoothed with
+	// deleted interpolation as in TnT (Brants 2000, http://www.aclweb.org/anthology/A00-1031)
+	Trigram
+)
+
+// Option changes how New builds a Tagger
+type Option func(*Tagger)
+
+// WithOrder selects a bigram (the default) or trigram transition model, any
+// other order makes Train return ErrBadOrder
+func WithOrder(order Order) Option {
+	return func(t *Tagger) {
+		t.order = order
+	}
+}
+
+// Paths further than this below the best one are dropped while decoding a
+// trigram lattice, the same beam TnT uses by default.
+var trigramBeam = math.Log(1000)
+
+// Keeps the trigram model from ever assigning a transition a zero probability
+// which would kill every path through a tag the corpus rarely uses.
+const minTransProb = 1e-12
+
+// Raw tag n-gram counts collected while reading the corpus. They are turned
+// into the interpolated trigram model once every file has been read, until
+// then the tag set is still growing so everything is kept in maps.
+type ngramCounts struct {
+	uni   map[int]float32
+	bi    map[[2]int]float32
+	tri   map[[3]int]float32
+	ctx   map[[2]int]float32 // how often each pair of tags was a trigram history
+	total float32
+}
+
+func newNgramCounts() *ngramCounts {
+	return &ngramCounts{
+		uni: make(map[int]float32),
+		bi:  make(map[[2]int]float32),
+		tri: make(map[[3]int]float32),
+		ctx: make(map[[2]int]float32),
+	}
+}
+
+// Counts one occurrence of currTag following prevTag2 and prevTag1
+func (c *ngramCounts) increment(prevTag2 int, prevTag1 int, currTag int) {
+	c.uni[currTag]++
+	c.bi[[2]int{prevTag1, currTag}]++
+	c.tri[[3]int{prevTag2, prevTag1, currTag}]++
+	c.ctx[[2]int{prevTag2, prevTag1}]++
+	c.total++
+}
+
+// Computes the interpolation weights for the unigram, bigram and trigram
+// estimates using deleted interpolation. Every trigram votes, with its count,
+// for whichever estimate predicts it best once that trigram is held out.
+func (c *ngramCounts) deletedInterpolation() [3]float32 {
+	var lambdas [3]float32
+	for trigram, count := range c.tri {
+		t1, t2, t3 := trigram[0], trigram[1], trigram[2]
+		scores := [3]float32{
+			heldOutRatio(c.uni[t3], c.total),
+			heldOutRatio(c.bi[[2]int{t2, t3}], c.uni[t2]),
+			heldOutRatio(count, c.ctx[[2]int{t1, t2}]),
+		}
+		best := 0
+		for i := 1; i < len(scores); i++ {
+			if scores[i] > scores[best] {
+				best = i
+			}
+		}
+		lambdas[best] += count
+	}
+
+	var total float32
+	for _, lambda := range lambdas {
+		total += lambda
+	}
+	if total == 0 { // nothing was counted, fall back to the unigrams
+		return [3]float32{1, 0, 0}
+	}
+	for i := range lambdas {
+		lambdas[i] /= total
+	}
+	return lambdas
+}
+
+// (count - 1) / (context - 1), the maximum likelihood estimate with the
+// current observation removed. Zero when the context was only seen once.
+func heldOutRatio(count float32, context float32) float32 {
+	if context <= 1 {
+		return 0
+	}
+	return (count - 1) / (context - 1)
+}
+
+// Converts the counts into the maximum likelihood estimates and interpolation
+// weights stored on the Tagger, numOfTags is the size of the final tag set
+func (c *ngramCounts) apply(t *Tagger, numOfTags int) {
+	t.lambdas = c.deletedInterpolation()
+
+	t.tagProb = make([]float32, numOfTags)
+	t.bigramProb = make([][]float32, numOfTags)
+	for row := range t.bigramProb {
+		t.bigramProb[row] = make([]float32, numOfTags)
+		if c.total > 0 {
+			t.tagProb[row] = c.uni[row] / c.total
+		}
+	}
+	// the start of the corpus is a history that was never counted as a tag
+	history := make(map[int]float32)
+	for bigram, count := range c.bi {
+		history[bigram[0]] += count
+	}
+	for bigram, count := range c.bi {
+		t.bigramProb[bigram[0]][bigram[1]] = count / history[bigram[0]]
+	}
+
+	t.trigramProb = make(map[[3]int]float32, len(c.tri))
+	for trigram, count := range c.tri {
+		t.trigramProb[trigram] = count / c.ctx[[2]int{trigram[0], trigram[1]}]
+	}
+}
+
+// The log of the interpolated probability of currTag given the two before it
+func (t *Tagger) trigramTrans(prevTag2 int, prevTag1 int, currTag int) float64 {
+	prob := t.lambdas[0]*t.tagProb[currTag] +
+		t.lambdas[1]*t.bigramProb[prevTag1][currTag] +
+		t.lambdas[2]*t.trigramProb[[3]int{prevTag2, prevTag1, currTag}]
+	return math.Log(float64(prob) + minTransProb)
+}
+
+// a cell of the trigram lattice, the state is the last two tags of the path
+type trigramState struct {
+	prevTag int
+	currTag int
+	score   float64
+	backPtr int // the tag before prevTag on the best path to this state
+}
+
+// The second order version of viterbi. States are pairs of tags so the
+// lattice is kept sparse, only holding pairs a word could actually produce,
+// and pruned to a beam around the best path at every word.
+func (t *Tagger) viterbiTrigram(words []string) []string {
+	tags := make([]string, len(words))
+	if len(words) == 0 {
+		return tags
+	}
+
+	start, _ := t.tagSet.Index(sentenceEnd)
+	lattice := make([][]trigramState, len(words))
+	prevStates := []trigramState{{prevTag: start, currTag: start}}
+
+	for wrdIndex, word := range words {
+		emit := t.emissions(word)
+		var states []trigramState
+		seen := make(map[[2]int]int)
+		for _, prev := range prevStates {
+			for tagIndex, emitScore := range emit {
+				if emitScore == logZero {
+					continue
+				}
+				score := prev.score + t.trigramTrans(prev.prevTag, prev.currTag, tagIndex) + emitScore
+				key := [2]int{prev.currTag, tagIndex}
+				if i, ok := seen[key]; ok {
+					if score > states[i].score {
+						states[i].score = score
+						states[i].backPtr = prev.prevTag
+					}
+					continue
+				}
+				seen[key] = len(states)
+				states = append(states, trigramState{prevTag: prev.currTag, currTag: tagIndex, score: score, backPtr: prev.prevTag})
+			}
+		}
+		lattice[wrdIndex] = pruneStates(states)
+		prevStates = lattice[wrdIndex]
+	}
+
+	// pick the best final state and follow the backpointers home
+	last := len(words) - 1
+	best := lattice[last][0]
+	for _, state := range lattice[last][1:] {
+		if state.score > best.score {
+			best = state
+		}
+	}
+	prevTag, currTag := best.prevTag, best.currTag
+	for wrdIndex := last; wrdIndex >= 0; wrdIndex-- {
+		tags[wrdIndex] = t.tagSet.Tag(currTag)
+		if wrdIndex == 0 {
+			break
+		}
+		for _, state := range lattice[wrdIndex] {
+			if state.prevTag == prevTag && state.currTag == currTag {
+				prevTag, currTag = state.backPtr, prevTag
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// Drops every state that scores worse than the beam allows
+func pruneStates(states []trigramState) []trigramState {
+	best := logZero
+	for _, state := range states {
+		if state.score > best {
+			best = state.score
+		}
+	}
+	kept := states[:0]
+	for _, state := range states {
+		if state.score >= best-trigramBeam {
+			kept = append(kept, state)
+		}
+	}
+	return kept
+}
@@ -1,13 +1,11 @@
 package tagger
 
 import (
-	"bytes"
 	"regexp"
 	"strings"
-)
 
-// global const:
-const numOfTags int = 38
+	"github.com/modquiz/go-nltb/lib/tokenize"
+)
 
 // The character used to split the string from the part of speech tag in the Corpus
 const SPLITCHARS string = "/"
@@ -23,18 +21,36 @@ type TagFrequency struct {
 }
 
 // The Tagger Object
+// A Tagger is not changed after it has been trained or loaded, so one Tagger
+// can tag from any number of goroutines at once.
 type Tagger struct {
-	Dictionary  map[string][]TagFrequency
-	TransMatrix [][]float32
+	tagSet      *TagSet
+	dictionary  map[string][]TagFrequency
+	transMatrix [][]float32
+	// the log of transMatrix, looked up by viterbi
+	logTransMatrix [][]float64
+	// the second order model, only filled in when order is Trigram
+	order       Order
+	lambdas     [3]float32 // unigram, bigram, trigram interpolation weights
+	tagProb     []float32
+	bigramProb  [][]float32
+	trigramProb map[[3]int]float32
+	// the unknown word model, nil for models saved before it existed
+	suffixes *suffixModel
 	// for the copyright extraction
-	CopyrightDFA  map[Tri]int
-	CopyrightSyms string
+	copyrightDFA  map[Tri]int
+	copyrightSyms string
 }
 
+// A word of the input and its part of speech tag. The offsets locate the word
+// in the input, the ends are exclusive so input[ByteStart:ByteEnd] is the word.
 type TaggedWord struct {
 	Word      string
 	Tag       string
-	byteStart int
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
 }
 
 // three variable structure used in DFA translation
@@ -44,14 +60,50 @@ type Tri struct {
 	pos   string
 }
 
+// TagSet returns the inventory of tags the Tagger was trained with
+func (t *Tagger) TagSet() *TagSet {
+	return t.tagSet
+}
+
+// Order returns whether the Tagger is a bigram or a trigram model
+func (t *Tagger) Order() Order {
+	return t.order
+}
+
+// Lambdas returns the unigram, bigram and trigram interpolation weights of a
+// trigram model
+func (t *Tagger) Lambdas() [3]float32 {
+	return t.lambdas
+}
+
 // This is the counter of tag transitions. Moving from one part of speech tag
 // to the other. When reading the input corpus this function is called to
 // increment/make note of every part of speech tag transition.
 // transitionOccurances = transMatrix[prev POS tag][current POS tag]
+// The matrix grows whenever a tag is seen for the first time.
 func incrementTransMatrix(transMatrix *[][]float32, prevTagIndex int, currTagIndex int) {
+	if prevTagIndex >= len(*transMatrix) || currTagIndex >= len(*transMatrix) {
+		size := prevTagIndex
+		if currTagIndex > size {
+			size = currTagIndex
+		}
+		growTransMatrix(transMatrix, size+1)
+	}
 	(*transMatrix)[prevTagIndex][currTagIndex]++
 }
 
+// Grows the transition matrix to size x size keeping the counts already in it
+func growTransMatrix(transMatrix *[][]float32, size int) {
+	for row := range *transMatrix {
+		for len((*transMatrix)[row]) < size {
+			(*transMatrix)[row] = append((*transMatrix)[row], 0)
+		}
+	}
+	for len(*transMatrix) < size {
+		*transMatrix = append(*transMatrix, make([]float32, size))
+	}
+}
+
 // Given the unigram word dictionary, a word and the given part of speech
 // tag for the word this will increment if the word already existed in the dictionary
 // if the word did not this will create a new entry and set the times seen to 1
@@ -103,6 +155,7 @@ func convertDictToProb(dictionary map[string][]TagFrequency) {
 func convertTransMatrixToProb(transMatrix *[][]float32) {
 	// transMatrix is a global variable
 	var total float32
+	numOfTags := len(*transMatrix)
 
 	for row := 0; row < numOfTags; row++ {
 		total = float32(numOfTags)
@@ -218,45 +271,16 @@ func formatSent(rawBytes []byte) []byte {
 	return rawBytes
 }
 
-// returns true if the given byte is a white space character
-func isSpace(b ...byte) bool {
-
-	return bytes.Contains([]byte(" \n\r\t"), b)
-}
-
-// returns true if the given byte is a ASCII symbolic character
-func isSymbol(b ...byte) bool {
-	return bytes.Contains([]byte("~!`@#$%^&*()[]_+-=|}{:;'\"/\\.?><,"), b)
-}
-
 // Given a slice of raw bytes will convert this into a slice of
 // TaggedWord objects with no tag set. This slice of TaggedWord objects will
-// then be given to the tagger for determining the part of speech tag
+// then be given to the tagger for determining the part of speech tag.
+// The words are split by the Brown style rules of the tokenize package and
+// the byte and rune offsets of every word are filled in.
 func mkWrdArray(rawBytes []byte) []TaggedWord {
-
-	currByte := 0
-	wordStart := currByte
-	var taggedWords []TaggedWord = make([]TaggedWord, 0)
-
-	for currByte < len(rawBytes) {
-		if isSpace(rawBytes[currByte]) {
-			if wordStart != currByte { // add the word if I can
-				taggedWords = append(taggedWords, TaggedWord{Word: string(rawBytes[wordStart:currByte]), Tag: "", byteStart: wordStart})
-			}
-			currByte++
-			wordStart = currByte
-		} else if isSymbol(rawBytes[currByte]) {
-			if wordStart != currByte { // add the word if I can
-				taggedWords = append(taggedWords, TaggedWord{Word: string(rawBytes[wordStart:currByte]), Tag: "", byteStart: wordStart})
-			}
-			wordStart = currByte
-			currByte++
-			taggedWords = append(taggedWords, TaggedWord{Word: string(rawBytes[wordStart:currByte]), Tag: "", byteStart: wordStart})
-			wordStart = currByte
-		} else {
-			currByte++
-		}
+	tokens := tokenize.Tokenize(string(rawBytes))
+	taggedWords := make([]TaggedWord, len(tokens))
+	for i, token := range tokens {
+		taggedWords[i] = TaggedWord{Word: token.Text, ByteStart: token.ByteStart, ByteEnd: token.ByteEnd, RuneStart: token.RuneStart, RuneEnd: token.RuneEnd}
 	}
-	taggedWords = append(taggedWords, TaggedWord{Word: string(rawBytes[wordStart:currByte]), Tag: "", byteStart: wordStart})
 	return taggedWords
 }
@@ -0,0 +1,162 @@
+package tagger
+
+import (
+	"math"
+	"strings"
+)
+
+// logZero stands in for log(0) so that impossible states never win a max
+var logZero = math.Inf(-1)
+
+// The probability mass an unknown word gives to the tag guessed by tagUnkown.
+// The remainder is spread over every other tag so a strong transition can
+// still overrule the guess.
+const unknownGuessProb = 0.95
+
+// Returns the log probability of every part of speech tag emitting the given
+// word. Known words use the dictionary (first as written, then lower cased),
+// unknown words the distribution of the suffix model.
+func (t *Tagger) emissions(word string) []float64 {
+	numOfTags := t.tagSet.Len()
+	logProbs := make([]float64, numOfTags)
+	for tagIndex := range logProbs {
+		logProbs[tagIndex] = logZero
+	}
+
+	// sentence ending punctuation can only ever be a period
+	if word == "." || word == "?" || word == "!" {
+		if end, ok := t.tagSet.Index(sentenceEnd); ok {
+			logProbs[end] = 0
+			return logProbs
+		}
+	}
+
+	tagFreqs := t.dictionary[word]
+	if len(tagFreqs) == 0 {
+		tagFreqs = t.dictionary[strings.ToLower(word)]
+	}
+	seen := false
+	for _, tagObject := range tagFreqs {
+		tagIndex, ok := t.tagSet.Index(tagObject.tag)
+		if !ok || tagObject.freq <= 0 {
+			continue
+		}
+		logProbs[tagIndex] = math.Log(float64(tagObject.freq))
+		seen = true
+	}
+	if seen {
+		return logProbs
+	}
+
+	// never seen, or only seen with tags the model does not know about, so
+	// the suffix model guesses from the end of the word
+	if t.suffixes != nil {
+		if probs := t.suffixes.probs(word, numOfTags); probs != nil {
+			for tagIndex, prob := range probs {
+				if prob > 0 {
+					logProbs[tagIndex] = math.Log(prob)
+				}
+			}
+			return logProbs
+		}
+	}
+
+	// models without a suffix model fall back on the guess of tagUnkown.
+	// The guess may not be in the tag set of a model trained on another corpus.
+	guess, ok := t.tagSet.Index(tagUnkown(word))
+	if !ok || numOfTags == 1 {
+		for tagIndex := range logProbs {
+			logProbs[tagIndex] = -math.Log(float64(numOfTags))
+		}
+		return logProbs
+	}
+	rest := math.Log((1 - unknownGuessProb) / float64(numOfTags-1))
+	for tagIndex := range logProbs {
+		logProbs[tagIndex] = rest
+	}
+	logProbs[guess] = math.Log(unknownGuessProb)
+	return logProbs
+}
+
+// Finds the most likely sequence of part of speech tags for the given words
+// using the Viterbi algorithm. Scores are kept in log space so that long
+// sentences do not underflow, and every cell of the lattice keeps a
+// backpointer to the tag it came from so the best path can be walked back.
+func (t *Tagger) viterbi(words []string) []string {
+	tags := make([]string, len(words))
+	if len(words) == 0 {
+		return tags
+	}
+
+	numOfTags := t.tagSet.Len()
+
+	// lattice[wrdIndex][tagIndex] is the score of the best path ending in
+	// tagIndex at wrdIndex, backPtr holds the previous tag on that path
+	lattice := make([][]float64, len(words))
+	backPtr := make([][]int, len(words))
+
+	// the sentence is assumed to follow the end of a previous one
+	start, _ := t.tagSet.Index(sentenceEnd)
+	emit := t.emissions(words[0])
+	lattice[0] = make([]float64, numOfTags)
+	backPtr[0] = make([]int, numOfTags)
+	for tagIndex := 0; tagIndex < numOfTags; tagIndex++ {
+		lattice[0][tagIndex] = t.logTrans(start, tagIndex) + emit[tagIndex]
+		backPtr[0][tagIndex] = start
+	}
+
+	for wrdIndex := 1; wrdIndex < len(words); wrdIndex++ {
+		emit = t.emissions(words[wrdIndex])
+		lattice[wrdIndex] = make([]float64, numOfTags)
+		backPtr[wrdIndex] = make([]int, numOfTags)
+		for tagIndex := 0; tagIndex < numOfTags; tagIndex++ {
+			bestScore := logZero
+			bestPrev := 0
+			if emit[tagIndex] != logZero {
+				for prevIndex := 0; prevIndex < numOfTags; prevIndex++ {
+					if lattice[wrdIndex-1][prevIndex] == logZero {
+						continue // most tags can not produce the previous word
+					}
+					score := lattice[wrdIndex-1][prevIndex] + t.logTrans(prevIndex, tagIndex)
+					if score > bestScore {
+						bestScore = score
+						bestPrev = prevIndex
+					}
+				}
+			}
+			lattice[wrdIndex][tagIndex] = bestScore + emit[tagIndex]
+			backPtr[wrdIndex][tagIndex] = bestPrev
+		}
+	}
+
+	// pick the best final state and follow the backpointers home
+	last := len(words) - 1
+	bestTag := 0
+	for tagIndex := 1; tagIndex < numOfTags; tagIndex++ {
+		if lattice[last][tagIndex] > lattice[last][bestTag] {
+			bestTag = tagIndex
+		}
+	}
+	for wrdIndex := last; wrdIndex >= 0; wrdIndex-- {
+		tags[wrdIndex] = t.tagSet.Tag(bestTag)
+		bestTag = backPtr[wrdIndex][bestTag]
+	}
+	return tags
+}
+
+// The log probability of moving from prevTag to currTag
+func (t *Tagger) logTrans(prevTag int, currTag int) float64 {
+	return t.logTransMatrix[prevTag][currTag]
+}
+
+// Takes the log of the transition matrix once, instead of in the inner loop
+// of viterbi. Called whenever transMatrix is set.
+func (t *Tagger) setLogTrans() {
+	t.logTransMatrix = make([][]float64, len(t.transMatrix))
+	for prevTag, row := range t.transMatrix {
+		t.logTransMatrix[prevTag] = make([]float64, len(row))
+		for currTag, prob := range row {
+			t.logTransMatrix[prevTag][currTag] = math.Log(float64(prob))
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package tagset
+
+// Brown base tags to Universal Dependencies UPOS tags. Forms of be, do, have
+// and the modals are AUX as Brown does not separate their auxiliary use.
+var brownToUniversal = map[string]string{
+	// punctuation
+	".":  "PUNCT",
+	",":  "PUNCT",
+	":":  "PUNCT",
+	"(":  "PUNCT",
+	")":  "PUNCT",
+	"--": "PUNCT",
+	"``": "PUNCT",
+	"''": "PUNCT",
+	"'":  "PUNCT",
+	"*":  "PART", // not, n't
+
+	// determiners and quantifiers
+	"abl": "ADV", // pre-qualifier: quite, rather
+	"abn": "DET", // pre-quantifier: half, all
+	"abx": "DET", // both
+	"ap":  "ADJ", // post-determiner: many, several, next
+	"ap$": "ADJ",
+	"at":  "DET",
+	"dt":  "DET",
+	"dt$": "DET",
+	"dti": "DET",
+	"dts": "DET",
+	"dtx": "DET", // either, neither
+	"wdt": "DET",
+
+	// forms of be, do, have and the modals
+	"be":   "AUX",
+	"bed":  "AUX",
+	"bedz": "AUX",
+	"beg":  "AUX",
+	"bem":  "AUX",
+	"ben":  "AUX",
+	"ber":  "AUX",
+	"bez":  "AUX",
+	"do":   "AUX",
+	"dod":  "AUX",
+	"doz":  "AUX",
+	"hv":   "AUX",
+	"hvd":  "AUX",
+	"hvg":  "AUX",
+	"hvn":  "AUX",
+	"hvz":  "AUX",
+	"md":   "AUX",
+
+	// conjunctions, prepositions and particles
+	"cc": "CCONJ",
+	"cs": "SCONJ",
+	"in": "ADP",
+	"rp": "ADP",
+	"to": "PART",
+
+	// numbers
+	"cd":  "NUM",
+	"cd$": "NUM",
+	"od":  "ADJ", // ordinals: first, 2nd
+
+	// adjectives
+	"jj":  "ADJ",
+	"jj$": "ADJ",
+	"jjr": "ADJ",
+	"jjs": "ADJ",
+	"jjt": "ADJ",
+
+	// nouns
+	"nn":   "NOUN",
+	"nn$":  "NOUN",
+	"nns":  "NOUN",
+	"nns$": "NOUN",
+	"np":   "PROPN",
+	"np$":  "PROPN",
+	"nps":  "PROPN",
+	"nps$": "PROPN",
+	"nr":   "NOUN", // adverbial nouns: home, today
+	"nr$":  "NOUN",
+	"nrs":  "NOUN",
+
+	// pronouns
+	"ex":   "PRON",
+	"pn":   "PRON",
+	"pn$":  "PRON",
+	"pp":   "PRON", // a truncated pp$ in the corpus
+	"pp$":  "PRON",
+	"pp$$": "PRON",
+	"ppl":  "PRON",
+	"ppls": "PRON",
+	"ppo":  "PRON",
+	"pps":  "PRON",
+	"ppss": "PRON",
+	"wp$":  "PRON",
+	"wpo":  "PRON",
+	"wps":  "PRON",
+
+	// adverbs
+	"ql":  "ADV",
+	"qlp": "ADV",
+	"rb":  "ADV",
+	"rb$": "ADV",
+	"rbr": "ADV",
+	"rbt": "ADV",
+	"rn":  "ADV",
+	"wql": "ADV",
+	"wrb": "ADV",
+
+	// verbs
+	"vb":  "VERB",
+	"vbd": "VERB",
+	"vbg": "VERB",
+	"vbn": "VERB",
+	"vbz": "VERB",
+
+	// everything else
+	"uh":  "INTJ",
+	"nil": "X",
+}
+
+// Brown base tags to Penn Treebank tags. Penn splits possessives into a
+// separate POS token, a single Brown token keeps the tag of its noun.
+var brownToPenn = map[string]string{
+	// punctuation
+	".":  ".",
+	",":  ",",
+	":":  ":",
+	"(":  "-LRB-",
+	")":  "-RRB-",
+	"--": ":",
+	"``": "``",
+	"''": "''",
+	"'":  "''",
+	"*":  "RB", // not, n't
+
+	// determiners and quantifiers
+	"abl": "PDT",
+	"abn": "PDT",
+	"abx": "DT",
+	"ap":  "JJ",
+	"ap$": "JJ",
+	"at":  "DT",
+	"dt":  "DT",
+	"dt$": "DT",
+	"dti": "DT",
+	"dts": "DT",
+	"dtx": "DT",
+	"wdt": "WDT",
+
+	// forms of be, do, have and the modals
+	"be":   "VB",
+	"bed":  "VBD",
+	"bedz": "VBD",
+	"beg":  "VBG",
+	"bem":  "VBP",
+	"ben":  "VBN",
+	"ber":  "VBP",
+	"bez":  "VBZ",
+	"do":   "VBP",
+	"dod":  "VBD",
+	"doz":  "VBZ",
+	"hv":   "VBP",
+	"hvd":  "VBD",
+	"hvg":  "VBG",
+	"hvn":  "VBN",
+	"hvz":  "VBZ",
+	"md":   "MD",
+
+	// conjunctions, prepositions and particles
+	"cc": "CC",
+	"cs": "IN",
+	"in": "IN",
+	"rp": "RP",
+	"to": "TO",
+
+	// numbers
+	"cd":  "CD",
+	"cd$": "CD",
+	"od":  "JJ",
+
+	// adjectives
+	"jj":  "JJ",
+	"jj$": "JJ",
+	"jjr": "JJR",
+	"jjs": "JJS",
+	"jjt": "JJS",
+
+	// nouns
+	"nn":   "NN",
+	"nn$":  "NN",
+	"nns":  "NNS",
+	"nns$": "NNS",
+	"np":   "NNP",
+	"np$":  "NNP",
+	"nps":  "NNPS",
+	"nps$": "NNPS",
+	"nr":   "NN",
+	"nr$":  "NN",
+	"nrs":  "NNS",
+
+	// pronouns
+	"ex":   "EX",
+	"pn":   "NN",
+	"pn$":  "NN",
+	"pp":   "PRP$",
+	"pp$":  "PRP$",
+	"pp$$": "PRP",
+	"ppl":  "PRP",
+	"ppls": "PRP",
+	"ppo":  "PRP",
+	"pps":  "PRP",
+	"ppss": "PRP",
+	"wp$":  "WP$",
+	"wpo":  "WP",
+	"wps":  "WP",
+
+	// adverbs
+	"ql":  "RB",
+	"qlp": "RB",
+	"rb":  "RB",
+	"rb$": "RB",
+	"rbr": "RBR",
+	"rbt": "RBS",
+	"rn":  "RB",
+	"wql": "WRB",
+	"wrb": "WRB",
+
+	// verbs
+	"vb":  "VB",
+	"vbd": "VBD",
+	"vbg": "VBG",
+	"vbn": "VBN",
+	"vbz": "VBZ",
+
+	// everything else
+	"uh":  "UH",
+	"nil": "-NONE-",
+}
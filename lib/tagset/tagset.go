@@ -0,0 +1,116 @@
+// Package tagset converts part of speech tags between tag sets, in the same
+// way as NLTK's map_tag. The tagger is trained on the Brown corpus so its
+// tags can be mapped to the Universal Dependencies UPOS tags
+// (http://universaldependencies.org/u/pos/) or the Penn Treebank tags.
+package tagset
+
+import (
+	"errors"
+	"strings"
+)
+
+// The names of the supported tag sets
+const (
+	Brown     = "brown"
+	Universal = "universal"
+	Penn      = "penn"
+)
+
+// ErrUnknownMapping is returned by Lookup when there is no table to map
+// between the two tag sets
+var ErrUnknownMapping = errors.New("No mapping between these tag sets")
+
+// Mapping converts tags from one tag set to another
+type Mapping struct {
+	From     string
+	To       string
+	table    map[string]string
+	foreign  string // the tag for words the corpus marked as foreign
+	fallback string // the tag for anything not in the table
+}
+
+// every mapping shipped with the package, keyed by from then to
+var mappings = map[string]map[string]*Mapping{
+	Brown: {
+		Universal: {From: Brown, To: Universal, table: brownToUniversal, foreign: "X", fallback: "X"},
+		Penn:      {From: Brown, To: Penn, table: brownToPenn, foreign: "FW", fallback: "FW"},
+	},
+}
+
+// Lookup returns the Mapping between the two tag sets. Mapping a tag set to
+// itself returns a Mapping that leaves every tag as it is.
+func Lookup(from string, to string) (*Mapping, error) {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if from == to {
+		return &Mapping{From: from, To: to}, nil
+	}
+	if mapping, ok := mappings[from][to]; ok {
+		return mapping, nil
+	}
+	return nil, ErrUnknownMapping
+}
+
+// MapTag converts a single tag from one tag set to another, NLTK style.
+// Tags that can not be mapped are returned unchanged.
+func MapTag(from string, to string, tag string) string {
+	mapping, err := Lookup(from, to)
+	if err != nil {
+		return tag
+	}
+	return mapping.Map(tag)
+}
+
+// Map converts the tag, anything the table does not know becomes the
+// fallback tag of the target tag set (X for Universal, FW for Penn)
+func (m *Mapping) Map(tag string) string {
+	if mapped, ok := m.Lookup(tag); ok {
+		return mapped
+	}
+	return m.fallback
+}
+
+// Lookup converts the tag and reports whether the table knew about it
+func (m *Mapping) Lookup(tag string) (string, bool) {
+	if m.table == nil { // identity mapping
+		return tag, true
+	}
+	if mapped, ok := m.table[tag]; ok {
+		return mapped, true
+	}
+
+	base, foreign := brownBase(tag)
+	if foreign {
+		return m.foreign, true
+	}
+	mapped, ok := m.table[base]
+	return mapped, ok
+}
+
+// Reduces a Brown tag to the base tag the tables are written for.
+// Brown decorates tags with -tl (title), -hl (headline) and -nc (cited word)
+// suffixes, marks foreign words with a fw- prefix, and joins the tags of
+// contractions with a + (ppss+md for "I'll"), of which the first is used.
+// Verbs merged with n't carry a * (md* for "can't") and keep their verb tag.
+// A handful of tags in the corpus are truncated, e.g. -t for -tl.
+func brownBase(tag string) (string, bool) {
+	tag = strings.ToLower(tag)
+	if strings.HasPrefix(tag, "fw-") {
+		return tag, true
+	}
+	if plus := strings.Index(tag, "+"); plus > 0 {
+		tag = tag[:plus]
+	}
+	for {
+		trimmed := tag
+		for _, suffix := range []string{"-hl", "-tl", "-nc", "-t", "-n"} {
+			trimmed = strings.TrimSuffix(trimmed, suffix)
+		}
+		if trimmed == tag {
+			if len(tag) > 1 {
+				tag = strings.TrimSuffix(tag, "*")
+			}
+			return tag, false
+		}
+		tag = trimmed
+	}
+}
@@ -0,0 +1,74 @@
+package tagset
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// every tag used in the Brown corpus shipped with the repository
+func brownTags(t *testing.T) map[string]bool {
+	files, err := filepath.Glob(filepath.Join("..", "..", "brown", "c*"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("could not find the brown corpus: %v", err)
+	}
+	tags := make(map[string]bool)
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, word := range strings.Fields(string(raw)) {
+			if split := strings.LastIndex(word, "/"); split > 0 {
+				tags[word[split+1:]] = true
+			}
+		}
+	}
+	return tags
+}
+
+func TestMapping_everyBrownTag(t *testing.T) {
+	tags := brownTags(t)
+	for _, to := range []string{Universal, Penn} {
+		mapping, err := Lookup(Brown, to)
+		if err != nil {
+			t.Fatalf("Lookup(%v, %v) error = %v", Brown, to, err)
+		}
+		for tag := range tags {
+			if _, ok := mapping.Lookup(tag); !ok {
+				t.Errorf("Mapping(%v).Lookup(%q) is not mapped", to, tag)
+			}
+		}
+	}
+}
+
+func TestMapTag(t *testing.T) {
+	tests := []struct {
+		to   string
+		tag  string
+		want string
+	}{
+		{Universal, "at", "DET"},
+		{Universal, "nn-tl", "NOUN"},
+		{Universal, "np$", "PROPN"},
+		{Universal, "bez", "AUX"},
+		{Universal, "ppss+md", "PRON"},
+		{Universal, "fw-nn", "X"},
+		{Universal, "no-such-tag", "X"},
+		{Penn, "at", "DT"},
+		{Penn, "nns-hl", "NNS"},
+		{Penn, "bedz", "VBD"},
+		{Penn, "(", "-LRB-"},
+		{Penn, "fw-in+at-tl", "FW"},
+		{Brown, "nn-tl", "nn-tl"},
+		{"klingon", "nn", "nn"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.to+"/"+tt.tag, func(t *testing.T) {
+			if got := MapTag(Brown, tt.to, tt.tag); got != tt.want {
+				t.Errorf("MapTag(%v, %v) = %v, want %v", tt.to, tt.tag, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+// Package tokenize splits text into words with rules modelled on NLTK's
+// word_tokenize and the Penn Treebank tokenizer. It understands contractions,
+// abbreviations, numbers, URLs, email addresses, emoji and Unicode
+// punctuation, and records where every token is in the input.
+package tokenize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of text a token holds
+type Kind int
+
+const (
+	// Word is any run of letters, including abbreviations and contractions
+	Word Kind = iota
+	// Number is a number, possibly with decimals, separators or a percent sign
+	Number
+	// Punctuation is a punctuation mark, or a run like ... or --
+	Punctuation
+	// Symbol is a symbol that is not an emoji, like $ or +
+	Symbol
+	// URL is a web address
+	URL
+	// Email is an email address
+	Email
+	// Emoji is an emoji, including modifiers and joined sequences
+	Emoji
+)
+
+// Token is a piece of the input. The ends are exclusive so
+// text[ByteStart:ByteEnd] is the token.
+type Token struct {
+	Text      string
+	Kind      Kind
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+}
+
+// Tokenizer holds the rules used to split text
+type Tokenizer struct {
+	// SplitContractions splits clitics off their word as the Penn Treebank
+	// does, "don't" becomes "do" "n't" and "John's" becomes "John" "'s".
+	// The Brown Corpus keeps them together.
+	SplitContractions bool
+	// Abbreviations keep their trailing period, unless it ends the text or
+	// is followed by a capitalised word, where it most likely also ends the
+	// sentence. Keys are lower case and without the period.
+	Abbreviations map[string]bool
+	// Titles are abbreviations that come before names, so they keep their
+	// period before a capitalised word too.
+	Titles map[string]bool
+}
+
+// Default tokenizes the way the Brown Corpus is tokenized, it is the one the
+// part of speech tagger uses
+var Default = &Tokenizer{Abbreviations: Abbreviations, Titles: Titles}
+
+// Treebank tokenizes the way the Penn Treebank is tokenized
+var Treebank = &Tokenizer{SplitContractions: true, Abbreviations: Abbreviations, Titles: Titles}
+
+// Abbreviations is the default set of abbreviations that keep their period.
+// Words like "no" and "co" are left out as they end sentences more often than
+// they are abbreviated.
+var Abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "rev": true,
+	"sr": true, "jr": true, "st": true, "mt": true, "gen": true, "gov": true,
+	"sen": true, "rep": true, "col": true, "capt": true, "lt": true, "sgt": true,
+	"inc": true, "ltd": true, "corp": true, "bros": true,
+	"vs": true, "etc": true, "cf": true, "approx": true,
+	"jan": true, "feb": true, "apr": true, "jun": true, "jul": true,
+	"aug": true, "sep": true, "sept": true, "oct": true, "nov": true,
+	"ave": true, "blvd": true, "dept": true, "univ": true, "fig": true, "vol": true,
+}
+
+// Titles is the default set of abbreviations that come before names
+var Titles = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "rev": true,
+	"st": true, "mt": true, "gen": true, "gov": true, "sen": true, "rep": true,
+	"col": true, "capt": true, "lt": true, "sgt": true,
+}
+
+// Capitalised words that start a sentence far more often than they follow
+// a middle initial, the period of "Plan A. Then" ends the sentence
+var sentenceStarters = map[string]bool{
+	"a": true, "an": true, "the": true, "this": true, "that": true, "these": true,
+	"those": true, "it": true, "he": true, "she": true, "we": true, "they": true,
+	"you": true, "i": true, "his": true, "her": true, "its": true, "our": true,
+	"their": true, "my": true, "then": true, "but": true, "and": true, "or": true,
+	"so": true, "there": true, "if": true, "when": true, "as": true, "in": true,
+	"on": true, "at": true, "after": true, "however": true, "now": true,
+}
+
+// Tokenize splits the text with the Default tokenizer
+func Tokenize(text string) []Token {
+	return Default.Tokenize(text)
+}
+
+// Words splits the text with the Default tokenizer and returns the text of
+// every token
+func Words(text string) []string {
+	tokens := Default.Tokenize(text)
+	words := make([]string, len(tokens))
+	for i := range tokens {
+		words[i] = tokens[i].Text
+	}
+	return words
+}
+
+var (
+	urlPattern     = regexp.MustCompile(`^(?i:(?:https?|ftp)://[^\s<>"]+|www\.[^\s<>"]+\.[^\s<>"]+)`)
+	emailPattern   = regexp.MustCompile(`^[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+`)
+	numberPattern  = regexp.MustCompile(`^\p{Nd}+(?:[.,:/]\p{Nd}+)*(?:-\p{Nd}+(?:/\p{Nd}+)?)*%?`)
+	acronymPattern = regexp.MustCompile(`^(?:\p{L}\.){2,}`)
+)
+
+// The suffixes split off by SplitContractions, after normalizing ’ to '
+var clitics = []string{"'s", "'re", "'ve", "'ll", "'d", "'m"}
+
+// Tokenize splits the text into tokens
+func (tk *Tokenizer) Tokenize(text string) []Token {
+	tokens := make([]Token, 0)
+	add := func(start int, end int, kind Kind) {
+		tokens = append(tokens, Token{Text: text[start:end], Kind: kind, ByteStart: start, ByteEnd: end})
+	}
+
+	i := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsSpace(r) || r == utf8.RuneError && size == 1:
+			i += size
+		case isCJK(r):
+			add(i, i+size, Word)
+			i += size
+		case isEmoji(r):
+			end := emojiEnd(text, i)
+			add(i, end, Emoji)
+			i = end
+		case isWordRune(r):
+			for _, token := range tk.word(text, i) {
+				add(token.ByteStart, token.ByteEnd, token.Kind)
+				i = token.ByteEnd
+			}
+		default:
+			end := punctEnd(text, i)
+			kind := Punctuation
+			if unicode.IsSymbol(r) {
+				kind = Symbol
+			}
+			add(i, end, kind)
+			i = end
+		}
+	}
+
+	setRuneOffsets(text, tokens)
+	return tokens
+}
+
+// Scans the token starting with a letter or digit at start. It is returned
+// as more than one token when a contraction is split.
+func (tk *Tokenizer) word(text string, start int) []Token {
+	rest := text[start:]
+	if loc := urlPattern.FindStringIndex(rest); loc != nil {
+		return []Token{{Kind: URL, ByteStart: start, ByteEnd: start + trimURL(rest[:loc[1]])}}
+	}
+	if loc := emailPattern.FindStringIndex(rest); loc != nil {
+		return []Token{{Kind: Email, ByteStart: start, ByteEnd: start + loc[1]}}
+	}
+	if loc := numberPattern.FindStringIndex(rest); loc != nil {
+		if next, _ := utf8.DecodeRuneInString(rest[loc[1]:]); !isWordRune(next) {
+			return []Token{{Kind: Number, ByteStart: start, ByteEnd: start + loc[1]}}
+		}
+		// a number with its unit stays together, 3.14m like the 1960s
+		return []Token{{Kind: Word, ByteStart: start, ByteEnd: wordRunEnd(text, start+loc[1])}}
+	}
+	if loc := acronymPattern.FindStringIndex(rest); loc != nil {
+		return []Token{{Kind: Word, ByteStart: start, ByteEnd: start + loc[1]}}
+	}
+
+	// a run of word runes, joined over hyphens and apostrophes
+	end := start
+	for {
+		end = wordRunEnd(text, end)
+		joiner, size := utf8.DecodeRuneInString(text[end:])
+		if joiner != '-' && !isApostrophe(joiner) {
+			break
+		}
+		if next, _ := utf8.DecodeRuneInString(text[end+size:]); !isWordRune(next) {
+			break
+		}
+		end += size
+	}
+	word := text[start:end]
+
+	next, size := utf8.DecodeRuneInString(text[end:])
+	switch {
+	case next == '.' && tk.keepsPeriod(word, text[end+size:]):
+		end += size // Mr. and the J. of John J. Smith
+	case isApostrophe(next) && !tk.SplitContractions && (strings.HasSuffix(word, "s") || strings.HasSuffix(word, "S")):
+		// the plural possessive of ladies' but not a closing quote
+		if after, _ := utf8.DecodeRuneInString(text[end+size:]); !isWordRune(after) {
+			end += size
+		}
+	}
+
+	if tk.SplitContractions {
+		if split := contractionSplit(text[start:end]); split > 0 {
+			return []Token{
+				{Kind: Word, ByteStart: start, ByteEnd: start + split},
+				{Kind: Word, ByteStart: start + split, ByteEnd: end},
+			}
+		}
+	}
+	return []Token{{Kind: Word, ByteStart: start, ByteEnd: end}}
+}
+
+// Whether the period after word is part of it, rest is the text after the
+// period. The period of an abbreviation or initial that ends a sentence is a
+// token of its own.
+func (tk *Tokenizer) keepsPeriod(word string, rest string) bool {
+	lower := strings.ToLower(word)
+	initial := isInitial(word)
+	if !tk.Abbreviations[lower] && !initial {
+		return false
+	}
+	if rest == "" {
+		return false
+	}
+	space, size := utf8.DecodeRuneInString(rest)
+	if !unicode.IsSpace(space) {
+		return true
+	}
+	after := strings.TrimLeftFunc(rest[size:], unicode.IsSpace)
+	if first, _ := utf8.DecodeRuneInString(after); !unicode.IsUpper(first) {
+		return true
+	}
+	switch {
+	case tk.Titles[lower]:
+		return true
+	case initial:
+		nextWord := after[:wordRunEnd(after, 0)]
+		return !sentenceStarters[strings.ToLower(nextWord)]
+	}
+	return false
+}
+
+// Returns where a contraction splits into its word and clitic, or 0
+func contractionSplit(word string) int {
+	normal := strings.ToLower(strings.Replace(word, "’", "'", -1))
+	// the normalized word can be shorter, so offsets are found from the end
+	if strings.HasSuffix(normal, "n't") && len(normal) > 3 {
+		return len(word) - suffixBytes(word, 3)
+	}
+	for _, clitic := range clitics {
+		if strings.HasSuffix(normal, clitic) && len(normal) > len(clitic) {
+			return len(word) - suffixBytes(word, utf8.RuneCountInString(clitic))
+		}
+	}
+	return 0
+}
+
+// the number of bytes taken by the last n runes of s
+func suffixBytes(s string, n int) int {
+	bytes := 0
+	for ; n > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(s[:len(s)-bytes])
+		bytes += size
+	}
+	return bytes
+}
+
+// URLs often end a sentence or sit in brackets, the punctuation is not theirs
+func trimURL(url string) int {
+	return len(strings.TrimRight(url, ".,;:!?)]}'\""))
+}
+
+// Where the run of word runes starting at start ends
+func wordRunEnd(text string, start int) int {
+	end := start
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(r) {
+			break
+		}
+		end += size
+	}
+	return end
+}
+
+// Where the punctuation starting at start ends. Ellipses, dashes and the
+// doubled backquotes and apostrophes Brown uses as quotes are one token,
+// anything else is a single rune.
+func punctEnd(text string, start int) int {
+	r, size := utf8.DecodeRuneInString(text[start:])
+	if r != '.' && r != '-' && r != '`' && r != '\'' {
+		return start + size
+	}
+	end := start + size
+	for end < len(text) && rune(text[end]) == r {
+		end++
+	}
+	if r == '\'' && end-start > 2 {
+		return start + 2 // '' followed by an apostrophe
+	}
+	return end
+}
+
+// Letters, digits and combining marks make up words. CJK is split per
+// character as it is written without spaces.
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_') && !isCJK(r)
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// A single capital letter, the initial of a name
+func isInitial(word string) bool {
+	r, size := utf8.DecodeRuneInString(word)
+	return size == len(word) && unicode.IsUpper(r)
+}
+
+func isEmoji(r rune) bool {
+	return r >= 0x1F000 && r <= 0x1FAFF || r >= 0x2600 && r <= 0x27BF || r >= 0x2300 && r <= 0x23FF
+}
+
+// Emoji can be followed by skin tones and variation selectors, joined to
+// more emoji with a zero width joiner, or paired up as regional indicators
+// to make a flag
+func emojiEnd(text string, start int) int {
+	first, end := utf8.DecodeRuneInString(text[start:])
+	end += start
+	if isRegionalIndicator(first) {
+		if next, size := utf8.DecodeRuneInString(text[end:]); isRegionalIndicator(next) {
+			return end + size
+		}
+		return end
+	}
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		switch {
+		case r == 0xFE0F || r >= 0x1F3FB && r <= 0x1F3FF || r >= 0xE0020 && r <= 0xE007F:
+			end += size
+		case r == 0x200D:
+			next, nextSize := utf8.DecodeRuneInString(text[end+size:])
+			if !isEmoji(next) {
+				return end
+			}
+			end += size + nextSize
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// Fills in the rune offsets of tokens whose byte offsets are set
+func setRuneOffsets(text string, tokens []Token) {
+	currByte, currRune := 0, 0
+	for i := range tokens {
+		currRune += utf8.RuneCountInString(text[currByte:tokens[i].ByteStart])
+		tokens[i].RuneStart = currRune
+		currRune += utf8.RuneCountInString(tokens[i].Text)
+		tokens[i].RuneEnd = currRune
+		currByte = tokens[i].ByteEnd
+	}
+}
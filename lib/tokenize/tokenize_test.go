@@ -0,0 +1,60 @@
+package tokenize
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTokenizer_Tokenize(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer *Tokenizer
+		text      string
+		want      []string
+	}{
+		{"contractions kept", Default, "I don't know Atlanta's mayor.", []string{"I", "don't", "know", "Atlanta's", "mayor", "."}},
+		{"contractions split", Treebank, "I don't know Atlanta's mayor.", []string{"I", "do", "n't", "know", "Atlanta", "'s", "mayor", "."}},
+		{"curly contraction", Treebank, "They’re here", []string{"They", "’re", "here"}},
+		{"abbreviations", Default, "Mr. Smith of the U.S. and John F. Kennedy Jr. left.", []string{"Mr.", "Smith", "of", "the", "U.S.", "and", "John", "F.", "Kennedy", "Jr.", "left", "."}},
+		{"sentence final abbreviations", Default, "The answer is no. Ask Acme Inc. They know etc.", []string{"The", "answer", "is", "no", ".", "Ask", "Acme", "Inc", ".", "They", "know", "etc", "."}},
+		{"sentence final initials", Default, "Plan A. Then B.", []string{"Plan", "A", ".", "Then", "B", "."}},
+		{"units", Default, "It is 3.14m or 2.5kg long", []string{"It", "is", "3.14m", "or", "2.5kg", "long"}},
+		{"numbers", Default, "It costs 3.14 or 1,000 dollars, 50% off 1-1/2 in the 1960s.", []string{"It", "costs", "3.14", "or", "1,000", "dollars", ",", "50%", "off", "1-1/2", "in", "the", "1960s", "."}},
+		{"hyphens", Default, "Send an e-mail -- now...", []string{"Send", "an", "e-mail", "--", "now", "..."}},
+		{"urls and emails", Default, "See https://example.com/a?b=1. Or mail jo.doe@example.org!", []string{"See", "https://example.com/a?b=1", ".", "Or", "mail", "jo.doe@example.org", "!"}},
+		{"quotes", Default, "``Hi,'' she said, “fine” — ok", []string{"``", "Hi", ",", "''", "she", "said", ",", "“", "fine", "”", "—", "ok"}},
+		{"possessive plural", Default, "the ladies' man", []string{"the", "ladies'", "man"}},
+		{"emoji", Default, "great 👍🏽 job 👨‍👩‍👧 🇫🇷", []string{"great", "👍🏽", "job", "👨‍👩‍👧", "🇫🇷"}},
+		{"cjk", Default, "東京 is big", []string{"東", "京", "is", "big"}},
+		{"accents", Default, "naïve café", []string{"naïve", "café"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := tt.tokenizer.Tokenize(tt.text)
+			got := make([]string, len(tokens))
+			for i, token := range tokens {
+				got[i] = token.Text
+				if tt.text[token.ByteStart:token.ByteEnd] != token.Text {
+					t.Errorf("token %q has byte offsets %d:%d", token.Text, token.ByteStart, token.ByteEnd)
+				}
+				if token.RuneStart != utf8.RuneCountInString(tt.text[:token.ByteStart]) || token.RuneEnd-token.RuneStart != utf8.RuneCountInString(token.Text) {
+					t.Errorf("token %q has rune offsets %d:%d", token.Text, token.RuneStart, token.RuneEnd)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenizer.Tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize_kinds(t *testing.T) {
+	tokens := Tokenize("Visit www.example.com or call 555 😀 $")
+	want := []Kind{Word, URL, Word, Word, Number, Emoji, Symbol}
+	for i, token := range tokens {
+		if i >= len(want) || token.Kind != want[i] {
+			t.Errorf("Tokenize() token %q kind = %v", token.Text, token.Kind)
+		}
+	}
+}
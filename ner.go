@@ -1,37 +1,118 @@
 package nltb
 
-/*
 import (
-	"runtime"
+	"errors"
+	"os"
+	"sync"
 
 	"github.com/modquiz/go-nltb/lib/ner"
 )
 
+// NERModelEnv is the environment variable Init reads the MITIE model path
+// from when it is given no path
+const NERModelEnv = "MITIE_MODEL"
+
+var (
+	// ErrNoNERModel is returned by Init when it has no model path
+	ErrNoNERModel = errors.New("No NER model path given and " + NERModelEnv + " is not set")
+	// ErrNERClosed is returned by Do before Init or after Close
+	ErrNERClosed = errors.New("NER is not initialized or was closed")
+)
+
+// A named entity found in the input. The ends are exclusive so
+// input[ByteStart:ByteEnd] is the entity, which is also its Text.
+type Entity struct {
+	Text      string
+	Tag       string
+	Score     float64
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+}
+
+// Named entity recognition with a MITIE model, or with the pure Go
+// recognizer of lib/ner. A MITIE model is held in C memory until Close is
+// called.
 type NER struct {
-	extractor ner.Extractor
+	// guards extractor against Close while Do is running
+	mutex     sync.RWMutex
+	extractor ner.EntityExtractor
+	tags      []string
 }
 
-// Init parts of speech Tagging
-func (n *NER) Init() {
-	_, file, _, _ := runtime.Caller(0)
-	n.ext = ner.NewExtractor()
+/* Loads the MITIE model at path, or at $MITIE_MODEL when path is empty */
+func (n *NER) Init(path string) error {
+	if path == "" {
+		path = os.Getenv(NERModelEnv)
+	}
+	if path == "" {
+		return ErrNoNERModel
+	}
+	extractor, err := ner.NewExtractor(path)
+	if err != nil {
+		return err
+	}
+	n.InitExtractor(extractor)
+	return nil
+}
 
+/* Init named entity recognition with the English model of lib/ner, pure Go so it needs neither MITIE nor cgo */
+func (n *NER) InitEnglish() {
+	n.InitExtractor(ner.English())
 }
 
-// Do NER
-func (p *NER) Do(byteString []byte) []TaggedWord {
-	defer n.ext.Free()
-	if err != nil {
-		return
+/* Init named entity recognition with any extractor, e.g. a ner.Recognizer trained on your own data */
+func (n *NER) InitExtractor(extractor ner.EntityExtractor) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.extractor != nil {
+		n.extractor.Free()
+	}
+	n.extractor = extractor
+	n.tags = extractor.Tags()
+}
+
+/* Frees the model, the NER can be initialized again afterwards */
+func (n *NER) Close() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.extractor != nil {
+		n.extractor.Free()
+		n.extractor = nil
+		n.tags = nil
 	}
+	return nil
+}
 
-	tokens := ner.Tokenize(txt)
+/* Finds the named entities of the input, with their offsets into byteString */
+func (n *NER) Do(byteString []byte) ([]Entity, error) {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	if n.extractor == nil {
+		return nil, ErrNERClosed
+	}
 
-	es, err := n.ext.Extract(tokens)
+	found, err := n.extractor.ExtractText(string(byteString))
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	return es
+	entities := make([]Entity, len(found))
+	for i, entity := range found {
+		tag := entity.TagString
+		if tag == "" && entity.Tag >= 0 && entity.Tag < len(n.tags) {
+			tag = n.tags[entity.Tag]
+		}
+		entities[i] = Entity{
+			Text:      entity.Text,
+			Tag:       tag,
+			Score:     entity.Score,
+			ByteStart: entity.ByteStart,
+			ByteEnd:   entity.ByteEnd,
+			RuneStart: entity.RuneStart,
+			RuneEnd:   entity.RuneEnd,
+		}
+	}
+	return entities, nil
 }
-*/
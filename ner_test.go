@@ -1,46 +1,76 @@
 package nltb
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
 
 func TestNER_Init(t *testing.T) {
-	tests := []struct {
-		name string
-		n    *NER
-	}{
-	// TODO: Add test cases.
+	t.Setenv(NERModelEnv, "")
+	n := &NER{}
+	if err := n.Init(""); err != ErrNoNERModel {
+		t.Errorf("NER.Init() error = %v, want %v", err, ErrNoNERModel)
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.n.Init()
-		})
+	if err := n.Init(filepath.Join(t.TempDir(), "missing.dat")); err == nil {
+		t.Errorf("NER.Init() missing model error = nil")
+	}
+	if _, err := n.Do([]byte("Smith lives in Boston.")); err != ErrNERClosed {
+		t.Errorf("NER.Do() error = %v, want %v", err, ErrNERClosed)
+	}
+	if err := n.Close(); err != nil {
+		t.Errorf("NER.Close() error = %v", err)
 	}
 }
 
+// Needs a MITIE model, e.g. MITIE-models/english/ner_model.dat
 func TestNER_Do(t *testing.T) {
-	type args struct {
-		byteString []byte
-	}
-	tests := []struct {
-		name string
-		p    *NER
-		args args
-		want []TaggedWord
-	}{
-		{
-			name: "Test 1",
-			args: "This is a test of the TestNER",
-			want: []TaggedWord{},
-		},
-		// TODO: Add test cases.
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.p.Do(tt.args.byteString); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NER.Do() = %v, want %v", got, tt.want)
-			}
-		})
+	if os.Getenv(NERModelEnv) == "" {
+		t.Skip(NERModelEnv + " is not set")
+	}
+	n := &NER{}
+	if err := n.Init(""); err != nil {
+		t.Fatalf("NER.Init() error = %v", err)
+	}
+	defer n.Close()
+
+	input := "Café owner John Smith moved to Boston."
+	entities, err := n.Do([]byte(input))
+	if err != nil {
+		t.Fatalf("NER.Do() error = %v", err)
+	}
+	if len(entities) == 0 {
+		t.Fatalf("NER.Do() found no entities in %q", input)
+	}
+	for _, entity := range entities {
+		if input[entity.ByteStart:entity.ByteEnd] != entity.Text || string([]rune(input)[entity.RuneStart:entity.RuneEnd]) != entity.Text {
+			t.Errorf("NER.Do() offsets of %q do not match the input", entity.Text)
+		}
+		if entity.Tag == "" {
+			t.Errorf("NER.Do() %q has no tag", entity.Text)
+		}
+	}
+}
+
+func TestNER_InitEnglish(t *testing.T) {
+	n := &NER{}
+	n.InitEnglish()
+	defer n.Close()
+
+	input := "Mr. Smith flew from Boston to Paris."
+	entities, err := n.Do([]byte(input))
+	if err != nil {
+		t.Fatalf("NER.Do() error = %v", err)
+	}
+	found := make(map[string]string)
+	for _, entity := range entities {
+		if input[entity.ByteStart:entity.ByteEnd] != entity.Text {
+			t.Errorf("NER.Do() offsets of %q do not match the input", entity.Text)
+		}
+		found[entity.Text] = entity.Tag
+	}
+	if want := map[string]string{"Smith": "PERSON", "Boston": "LOCATION", "Paris": "LOCATION"}; !reflect.DeepEqual(found, want) {
+		t.Errorf("NER.Do() = %v, want %v", found, want)
 	}
 }
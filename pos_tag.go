@@ -2,36 +2,112 @@ package nltb
 
 import (
 	"os"
-	"path/filepath"
-	"runtime"
 
 	"github.com/jinzhu/copier"
+	"github.com/modquiz/go-nltb/brown"
+	"github.com/modquiz/go-nltb/lib/perceptron"
+	"github.com/modquiz/go-nltb/lib/punkt"
 	tagger "github.com/modquiz/go-nltb/lib/tagger"
+	"github.com/modquiz/go-nltb/lib/tagset"
 )
 
+// A tagged word with its location in the input, the ends are exclusive so
+// input[ByteStart:ByteEnd] is the word
 type TaggedWord struct {
 	Word      string
 	Tag       string
-	byteStart int
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
 }
 
+// Once initialized a POSTag only reads its model, so it can tag from many
+// goroutines at once
 type POSTag struct {
-	goTagger *tagger.Tagger
+	// Tagset the tags returned by Do are mapped to, tagset.Universal or
+	// tagset.Penn. Left empty the raw Brown tags are returned.
+	Tagset string
+	// the HMM of lib/tagger unless initialized with another tagger
+	goTagger tagger.SequenceTagger
 }
 
-/* Init parts of speech Tagging */
+/* Init parts of speech Tagging with the model pre-trained on the embedded Brown Corpus */
 func (p *POSTag) Init() {
-	_, file, _, _ := runtime.Caller(0)
-	filename := filepath.Dir(file) + string(os.PathSeparator) + "brown" + string(os.PathSeparator)
-	p.goTagger = tagger.New(filename)
+	p.goTagger = tagger.Brown()
+}
+
+/* Init parts of speech Tagging, trained on the corpus files in dir */
+func (p *POSTag) InitDir(dir string) error {
+	goTagger, err := tagger.TrainFS(os.DirFS(dir), ".")
+	if err != nil {
+		return err
+	}
+	p.goTagger = goTagger
+	return nil
+}
+
+/* Loads a pre-trained model saved with tagger.Tagger.SaveFile instead of training */
+func (p *POSTag) InitModel(path string) error {
+	goTagger, err := tagger.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	p.goTagger = goTagger
+	return nil
+}
+
+/* Init parts of speech Tagging with the averaged perceptron trained on the embedded Brown Corpus, more accurate than the HMM but slower to train */
+func (p *POSTag) InitPerceptron() error {
+	goTagger, err := perceptron.Train(tagger.NewFSSentenceReader(brown.Files, tagger.AssetNames()...))
+	if err != nil {
+		return err
+	}
+	p.goTagger = goTagger
+	return nil
+}
+
+/* Loads a perceptron model saved with perceptron.Tagger.SaveFile */
+func (p *POSTag) InitPerceptronModel(path string) error {
+	goTagger, err := perceptron.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	p.goTagger = goTagger
+	return nil
+}
+
+/* Init parts of speech Tagging with any tagger, e.g. one trained on your own corpus */
+func (p *POSTag) InitTagger(goTagger tagger.SequenceTagger) {
+	p.goTagger = goTagger
 }
 
 /* Does Parts of Speech Tagging */
 func (p *POSTag) Do(byteString []byte) []TaggedWord {
-	taggedWord := p.goTagger.TagBytes(byteString)
+	return p.DoTagset(byteString, p.Tagset)
+}
+
+/* Does Parts of Speech Tagging with the tags mapped to the given tagset */
+func (p *POSTag) DoTagset(byteString []byte, tagSet string) []TaggedWord {
+	taggedWord := tagger.TagBytes(p.goTagger, byteString)
 
 	var returnTaggedWord []TaggedWord
 	copier.Copy(&returnTaggedWord, &taggedWord)
+	if tagSet != "" && tagSet != tagset.Brown {
+		for i := range returnTaggedWord {
+			returnTaggedWord[i].Tag = tagset.MapTag(tagset.Brown, tagSet, returnTaggedWord[i].Tag)
+		}
+	}
 	//fmt.Println(returnTaggedWord)
 	return returnTaggedWord
 }
+
+/* Splits the input into sentences with the Punkt English model and tags each one, the offsets are into the whole byteString */
+func (p *POSTag) DoSentences(byteString []byte) [][]TaggedWord {
+	sentences := punkt.English().Sentences(string(byteString))
+	taggedSentences := make([][]TaggedWord, len(sentences))
+	for i, sentence := range sentences {
+		taggedSentences[i] = p.tagSentence(sentence.Text, sentence.ByteStart, sentence.RuneStart)
+	}
+	return taggedSentences
+}
@@ -0,0 +1,141 @@
+package nltb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// How much is read from the stream at a time
+const streamChunk = 32 << 10
+
+// Text without a paragraph break is split into sentences once this much is
+// buffered, the last sentence is kept back as it may not be complete
+const maxStreamBuffer = 64 << 10
+
+/* Reads r a piece at a time and calls fn with every tagged sentence in order. The offsets are into the whole stream and only a bounded part of it is held in memory. Stops at the first error returned by r or fn */
+func (p *POSTag) DoStream(r io.Reader, fn func(Sentence) error) error {
+	var buf []byte
+	chunk := make([]byte, streamChunk)
+	byteOffset, runeOffset := 0, 0
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		eof := err == io.EOF
+		if err != nil && !eof {
+			return err
+		}
+
+		// tag everything up to the last paragraph break, or to the end
+		cut := len(buf)
+		if !eof {
+			cut = lastParagraphBreak(buf)
+		}
+		var sentences []Sentence
+		if cut > 0 {
+			text := string(buf[:cut])
+			for _, paragraph := range splitParagraphs(text) {
+				sentences = append(sentences, p.tagParagraph(text, paragraph).Sentences...)
+			}
+		} else if len(buf) > maxStreamBuffer {
+			// a sentence longer than maxSentenceBytes is tagged in pieces
+			// by splitLong, all but the last piece are complete
+			sentences = p.tagText(string(buf))
+			if len(sentences) > 1 {
+				last := sentences[len(sentences)-1]
+				sentences = sentences[:len(sentences)-1]
+				cut = last.ByteStart
+			} else {
+				cut = lastWordBreak(buf)
+				sentences = p.tagText(string(buf[:cut]))
+			}
+		}
+
+		for _, sentence := range sentences {
+			if err := fn(shiftSentence(sentence, byteOffset, runeOffset)); err != nil {
+				return err
+			}
+		}
+		byteOffset += cut
+		runeOffset += utf8.RuneCount(buf[:cut])
+		// copy what is left so the buffer does not keep growing
+		buf = append([]byte(nil), buf[cut:]...)
+
+		if eof {
+			return nil
+		}
+	}
+}
+
+/* Tags r like DoStream and sends the sentences on a channel. The channel is closed at the end of r, the error channel then receives the error that stopped the stream, if any. Cancelling ctx stops reading */
+func (p *POSTag) DoStreamChan(ctx context.Context, r io.Reader) (<-chan Sentence, <-chan error) {
+	sentences := make(chan Sentence)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(sentences)
+		err := p.DoStream(r, func(sentence Sentence) error {
+			select {
+			case sentences <- sentence:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return sentences, errc
+}
+
+// Tags text as one paragraph
+func (p *POSTag) tagText(text string) []Sentence {
+	return p.tagParagraph(text, Paragraph{ByteEnd: len(text), RuneEnd: utf8.RuneCountInString(text)}).Sentences
+}
+
+// Returns the index just after the last white space in buf, or the start of
+// its last rune when there is no white space. The last word may continue in
+// the next read, so it is never cut.
+func lastWordBreak(buf []byte) int {
+	if space := bytes.LastIndexFunc(buf, unicode.IsSpace); space >= 0 {
+		_, size := utf8.DecodeRune(buf[space:])
+		return space + size
+	}
+	cut := len(buf) - 1
+	for cut > 0 && !utf8.RuneStart(buf[cut]) {
+		cut--
+	}
+	return cut
+}
+
+// Returns the index just after the last blank line in buf, 0 if there is
+// none
+func lastParagraphBreak(buf []byte) int {
+	end := bytes.LastIndexByte(buf, '\n')
+	for end > 0 {
+		start := bytes.LastIndexByte(buf[:end], '\n')
+		if start >= 0 && len(bytes.TrimSpace(buf[start+1:end])) == 0 {
+			return end + 1
+		}
+		end = start
+	}
+	return 0
+}
+
+// Moves a sentence and its words by the given offsets
+func shiftSentence(sentence Sentence, byteOffset int, runeOffset int) Sentence {
+	sentence.ByteStart += byteOffset
+	sentence.ByteEnd += byteOffset
+	sentence.RuneStart += runeOffset
+	sentence.RuneEnd += runeOffset
+	for i := range sentence.Words {
+		sentence.Words[i].ByteStart += byteOffset
+		sentence.Words[i].ByteEnd += byteOffset
+		sentence.Words[i].RuneStart += runeOffset
+		sentence.Words[i].RuneEnd += runeOffset
+	}
+	return sentence
+}
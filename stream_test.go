@@ -0,0 +1,89 @@
+package nltb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestPOSTag_DoStream(t *testing.T) {
+	posTagger := POSTag{}
+	posTagger.Init()
+
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{"paragraphs", "The jury met Mr. Smith. He said nothing.\n\n\nThe café was closed.\n \nIt rained."},
+		{"no paragraph breaks", strings.Repeat("The naïve jury met Mr. Smith on Friday. He said nothing at all! ", 2000)},
+		{"no sentence breaks", strings.Repeat("word ", 20000)},
+		{"multibyte sentence", strings.Repeat("the naïve jury sat in a café ", 2500)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Sentence
+			err := posTagger.DoStream(iotest.HalfReader(strings.NewReader(tt.document)), func(sentence Sentence) error {
+				got = append(got, sentence)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := posTagger.DoDocument([]byte(tt.document)).Sentences()
+			if len(got) != len(want) {
+				t.Fatalf("DoStream() gave %d sentences, want %d", len(got), len(want))
+			}
+			runes := []rune(tt.document)
+			for i := range got {
+				if !reflect.DeepEqual(got[i], want[i]) {
+					t.Fatalf("DoStream() sentence %d = %+v, want %+v", i, got[i], want[i])
+				}
+				if string(runes[got[i].RuneStart:got[i].RuneEnd]) != got[i].Text {
+					t.Fatalf("DoStream() sentence %q has rune offsets %d:%d", got[i].Text, got[i].RuneStart, got[i].RuneEnd)
+				}
+			}
+		})
+	}
+}
+
+func Test_lastWordBreak(t *testing.T) {
+	tests := []struct {
+		buf  string
+		want int
+	}{
+		{"naïve café", len("naïve ")},
+		{"naïve\u00a0café", len("naïve\u00a0")},
+		{"naïvecafé", len("naïvecaf")},
+		{"naïvecaf\xc3", len("naïvecaf")},
+	}
+	for _, tt := range tests {
+		if got := lastWordBreak([]byte(tt.buf)); got != tt.want {
+			t.Errorf("lastWordBreak(%q) = %d, want %d", tt.buf, got, tt.want)
+		}
+	}
+}
+
+func TestPOSTag_DoStreamChan(t *testing.T) {
+	posTagger := POSTag{}
+	posTagger.Init()
+
+	sentences, errc := posTagger.DoStreamChan(context.Background(), strings.NewReader("One day. Two days.\n\nThree."))
+	count := 0
+	for range sentences {
+		count++
+	}
+	if err := <-errc; err != nil || count != 3 {
+		t.Errorf("DoStreamChan() gave %d sentences, %v", count, err)
+	}
+
+	failure := errors.New("read failed")
+	sentences, errc = posTagger.DoStreamChan(context.Background(), iotest.ErrReader(failure))
+	for range sentences {
+	}
+	if err := <-errc; err != failure {
+		t.Errorf("DoStreamChan() error = %v, want %v", err, failure)
+	}
+}